@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// Classify infers the kind of the given error from well-known error values and types of the standard library:
+//
+//	os.ErrNotExist                     --> K.NotExist
+//	os.ErrPermission                   --> K.Permission
+//	context.DeadlineExceeded           --> K.Timeout
+//	context.Canceled                   --> K.Cancelled
+//	io.EOF, io.ErrUnexpectedEOF        --> K.IO
+//	net.Error with Timeout() == true   --> K.Timeout
+//	*net.DNSError                      --> K.NoNetRoute
+//	*net.OpError: connection refused   --> K.NoNetRoute
+//	*net.OpError: connection reset     --> K.IO
+//	other net.Error                    --> K.IO
+//
+// Errors are matched with errors.Is() and errors.As(), so wrapped errors are classified as well. If err is (or wraps) an
+// *Error with a kind other than K.Other, that kind is returned. Returns K.Other if err is nil or the kind cannot be
+// inferred.
+func Classify(err error) Kind {
+	if err == nil {
+		return K.Other
+	}
+	var e *Error
+	if stderrors.As(err, &e) {
+		if kind := e.Kind(); kind != K.Other {
+			return kind
+		}
+	}
+	switch {
+	case stderrors.Is(err, os.ErrNotExist):
+		return K.NotExist
+	case stderrors.Is(err, os.ErrPermission):
+		return K.Permission
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return K.Timeout
+	case stderrors.Is(err, context.Canceled):
+		return K.Cancelled
+	case stderrors.Is(err, io.EOF), stderrors.Is(err, io.ErrUnexpectedEOF):
+		return K.IO
+	}
+	return classifyNet(err)
+}
+
+// classifyNet infers the kind of network errors. Returns K.Other if err is not a network error.
+func classifyNet(err error) Kind {
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return K.Timeout
+	}
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return K.NoNetRoute
+	}
+	var opErr *net.OpError
+	if stderrors.As(err, &opErr) {
+		switch {
+		case stderrors.Is(opErr, syscall.ECONNREFUSED):
+			return K.NoNetRoute
+		case stderrors.Is(opErr, syscall.ECONNRESET):
+			return K.IO
+		}
+	}
+	if netErr != nil {
+		return K.IO
+	}
+	return K.Other
+}
+
+// EClassified creates a new error with E(args...), sets the given err as its cause and assigns the kind inferred by
+// Classify(err) as default kind. A kind passed explicitly in args takes precedence over the inferred kind:
+//
+//	errors.EClassified(err, "read config", "file", f)                  --> kind K.NotExist if err is os.ErrNotExist
+//	errors.EClassified(err, "read config", errors.K.Invalid, "file", f) --> kind K.Invalid
+//
+// If err is nil, EClassified is the same as E(args...).
+func EClassified(err error, args ...interface{}) *Error {
+	e := newErrorWithStack(args)
+	if err != nil {
+		_ = e.WithCause(err)
+		if kind := Classify(err); kind != K.Other {
+			_ = e.WithDefaultKind(kind)
+		}
+	}
+	return created(e)
+}
@@ -0,0 +1,108 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestClassify(t *testing.T) {
+	_, notExist := os.Open("/does/not/exist")
+	require.Error(t, notExist)
+
+	tests := []struct {
+		err  error
+		want errors.Kind
+	}{
+		{nil, errors.K.Other},
+		{errors.Str("unknown"), errors.K.Other},
+		{notExist, errors.K.NotExist},
+		{os.ErrNotExist, errors.K.NotExist},
+		{&fs.PathError{Op: "open", Path: "/etc/x", Err: fs.ErrPermission}, errors.K.Permission},
+		{context.DeadlineExceeded, errors.K.Timeout},
+		{context.Canceled, errors.K.Cancelled},
+		{io.EOF, errors.K.IO},
+		{io.ErrUnexpectedEOF, errors.K.IO},
+		{&net.AddrError{Err: "bad address", Addr: "x"}, errors.K.IO},
+		{os.ErrDeadlineExceeded, errors.K.Timeout},
+		{&net.DNSError{Err: "no such host", Name: "x.invalid", IsNotFound: true}, errors.K.NoNetRoute},
+		{&net.DNSError{Err: "i/o timeout", Name: "x.invalid", IsTimeout: true}, errors.K.Timeout},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, errors.K.NoNetRoute},
+		{&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, errors.K.IO},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}, errors.K.Timeout},
+		{fmt.Errorf("get: %w", &url.Error{Op: "Get", URL: "http://x", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}), errors.K.NoNetRoute},
+		{fmt.Errorf("wrapped: %w", context.Canceled), errors.K.Cancelled},
+		{errors.NoTrace("op", errors.K.Invalid), errors.K.Invalid},
+		{fmt.Errorf("wrapped: %w", errors.NoTrace("op", errors.K.Invalid)), errors.K.Invalid},
+		{errors.NoTrace("op", io.EOF), errors.K.IO},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.err), func(t *testing.T) {
+			require.Equal(t, test.want, errors.Classify(test.err))
+		})
+	}
+}
+
+func TestEClassified(t *testing.T) {
+	tests := []struct {
+		err  *errors.Error
+		want string
+	}{
+		{errors.EClassified(nil, "read"), "op [read] kind [unclassified error]"},
+		{errors.EClassified(errors.Str("unknown"), "read"), "op [read] kind [unclassified error] cause [unknown]"},
+		{
+			errors.EClassified(os.ErrNotExist, "read", "file", "a.txt"),
+			"op [read] kind [item does not exist] file [a.txt] cause [file does not exist]",
+		},
+		{
+			errors.EClassified(os.ErrNotExist, "read", errors.K.Invalid, "file", "a.txt"),
+			"op [read] kind [invalid] file [a.txt] cause [file does not exist]",
+		},
+		{
+			errors.EClassified(context.DeadlineExceeded, "query"),
+			"op [query] kind [operation timed out] cause [context deadline exceeded]",
+		},
+		{
+			errors.EClassified(errors.NoTrace("open", errors.K.Permission), "read"),
+			"op [read] kind [permission denied] cause:\n\top [open] kind [permission denied]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, test.err.ErrorNoTrace())
+		})
+	}
+}
+
+func TestEClassified_counter(t *testing.T) {
+	counter := errors.NewMapKindCounter()
+	errors.SetKindCounter(counter)
+	defer errors.SetKindCounter(nil)
+
+	_ = errors.EClassified(os.ErrNotExist, "read")
+	require.Equal(t, map[errors.Kind]int64{errors.K.NotExist: 1}, counter.Snapshot())
+}
+
+func TestClassify_dial(t *testing.T) {
+	// find a local port that refuses connections
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	_, err = net.Dial("tcp", addr)
+	require.Error(t, err)
+	require.Equal(t, errors.K.NoNetRoute, errors.Classify(err))
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"context"
+)
+
+// WithContextRef stores a reference to the given context in this error and returns this error instance for call
+// chaining. The context's values are not copied: fields are extracted only later with ResolveContext(), e.g. at logging
+// time, when the code creating the error does not know which context values are relevant.
+//
+// Storing a context is opt-in and keeps the context and all its values reachable for as long as the error is
+// referenced. Errors holding a context should therefore not be retained for longer than the request (or other unit of
+// work) the context belongs to, e.g. in caches or error lists of long-running processes. Call ResolveContext() or
+// WithContextRef(nil) to release the context.
+//
+// The context is not part of the error's text or JSON representation: it is dropped when the error is marshaled and is
+// not restored on unmarshaling.
+func (e *Error) WithContextRef(ctx context.Context) *Error {
+	if e == nil {
+		return e
+	}
+	e.ctxRef = ctx
+	return e
+}
+
+// ContextRef returns the context stored with WithContextRef() or nil if no context is stored.
+func (e *Error) ContextRef() context.Context {
+	if e == nil {
+		return nil
+	}
+	return e.ctxRef
+}
+
+// ResolveContext extracts fields from the context stored with WithContextRef() and adds them to this error. Each
+// extractor returns the key and value of a field; fields with an empty key are ignored:
+//
+//	err := errors.E("fetch", errors.K.Unavailable).WithContextRef(ctx)
+//	...
+//	err.ResolveContext(func(ctx context.Context) (string, interface{}) {
+//		return "request_id", ctx.Value(requestIDKey)
+//	})
+//	--> op [fetch] kind [service unavailable] request_id [a3b9f1]
+//
+// The context reference is released after the fields have been extracted. ResolveContext is a no-op if no context is
+// stored. Returns this error instance for call chaining.
+func (e *Error) ResolveContext(extractors ...func(context.Context) (string, interface{})) *Error {
+	if e == nil || e.ctxRef == nil {
+		return e
+	}
+	ctx := e.ctxRef
+	e.ctxRef = nil
+	for _, extract := range extractors {
+		if extract == nil {
+			continue
+		}
+		if key, val := extract(ctx); key != "" {
+			e.fields.Set(key, val)
+		}
+	}
+	return e
+}
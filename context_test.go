@@ -0,0 +1,50 @@
+package errors_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+type requestIDKey struct{}
+
+func requestID(ctx context.Context) (string, interface{}) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	if !ok {
+		return "", nil
+	}
+	return "request_id", id
+}
+
+func TestError_ResolveContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "r1")
+
+	err := errors.NoTrace("fetch", errors.K.Unavailable).WithContextRef(ctx)
+	require.Equal(t, ctx, err.ContextRef())
+	require.Equal(t, "op [fetch] kind [service unavailable]", err.Error())
+
+	// context is dropped on marshaling
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, `{"op":"fetch","kind":"service unavailable"}`, string(jsn))
+
+	err.ResolveContext(requestID, nil, func(context.Context) (string, interface{}) { return "", "ignored" })
+	require.Equal(t, "op [fetch] kind [service unavailable] request_id [r1]", err.Error())
+	require.Nil(t, err.ContextRef())
+
+	// no-op once the context has been released
+	err.ResolveContext(func(context.Context) (string, interface{}) { return "other", 1 })
+	require.Equal(t, "op [fetch] kind [service unavailable] request_id [r1]", err.Error())
+
+	// value missing in context
+	err = errors.NoTrace("fetch").WithContextRef(context.Background()).ResolveContext(requestID)
+	require.Equal(t, "op [fetch] kind [unclassified error]", err.Error())
+
+	var nilErr *errors.Error
+	require.Nil(t, nilErr.WithContextRef(ctx).ResolveContext(requestID))
+	require.Nil(t, nilErr.ContextRef())
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// KindCounter is the interface for counting created errors by kind. See SetKindCounter.
+type KindCounter interface {
+	// Inc increments the counter for the given kind.
+	Inc(kind Kind)
+}
+
+// kindCounter is the registered KindCounter. A nil value represents the default no-op counter.
+var kindCounter atomic.Pointer[KindCounter]
+
+// SetKindCounter registers the given counter, which is then incremented with the effective kind of every error created
+// with E(), NoTrace(), New() or NewNoTrace(). The counter is invoked synchronously on the goroutine that creates the
+// error and must therefore be fast and safe for concurrent use.
+//
+// Passing nil restores the default no-op counter.
+func SetKindCounter(c KindCounter) {
+	if c == nil {
+		kindCounter.Store(nil)
+		return
+	}
+	kindCounter.Store(&c)
+}
+
+// countKind increments the registered KindCounter with the effective kind of the given error.
+func countKind(e *Error) {
+	if c := kindCounter.Load(); c != nil {
+		(*c).Inc(e.Kind())
+	}
+}
+
+// MapKindCounter is a simple, in-memory KindCounter that is safe for concurrent use. It is useful for tests and
+// deployments that don't use a dedicated metrics library.
+type MapKindCounter struct {
+	mutex  sync.Mutex
+	counts map[Kind]int64
+}
+
+// NewMapKindCounter creates a new, empty MapKindCounter.
+func NewMapKindCounter() *MapKindCounter {
+	return &MapKindCounter{counts: make(map[Kind]int64)}
+}
+
+// Inc increments the counter for the given kind.
+func (c *MapKindCounter) Inc(kind Kind) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.counts[kind]++
+}
+
+// Snapshot returns a copy of the current counts.
+func (c *MapKindCounter) Snapshot() map[Kind]int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	res := make(map[Kind]int64, len(c.counts))
+	for kind, count := range c.counts {
+		res[kind] = count
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestSetKindCounter(t *testing.T) {
+	counter := errors.NewMapKindCounter()
+	errors.SetKindCounter(counter)
+	defer errors.SetKindCounter(nil)
+
+	_ = errors.E("op", errors.K.IO)
+	_ = errors.NoTrace("op", errors.K.IO, io.EOF)
+	_ = errors.E("op", errors.E(errors.K.NotExist))
+	_ = errors.New(errors.WithKindOpt(errors.K.Invalid))
+	_ = errors.Wrap(errors.E(errors.K.Invalid)) // wrapping an *Error doesn't create a new error
+
+	require.Equal(t, map[errors.Kind]int64{
+		errors.K.IO:       2,
+		errors.K.NotExist: 2,
+		errors.K.Invalid:  2,
+	}, counter.Snapshot())
+
+	errors.SetKindCounter(nil)
+	_ = errors.E("op", errors.K.IO)
+	require.Equal(t, int64(2), counter.Snapshot()[errors.K.IO])
+}
+
+func TestSetKindCounterFromContext(t *testing.T) {
+	counter := errors.NewMapKindCounter()
+	errors.SetKindCounter(counter)
+	defer errors.SetKindCounter(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = errors.FromContext(ctx, "op")
+
+	require.Equal(t, map[errors.Kind]int64{errors.K.Cancelled: 1}, counter.Snapshot())
+}
+
+func TestMapKindCounter(t *testing.T) {
+	counter := errors.NewMapKindCounter()
+	require.Empty(t, counter.Snapshot())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				counter.Inc(errors.K.IO)
+			}
+		}()
+	}
+	wg.Wait()
+
+	snapshot := counter.Snapshot()
+	require.Equal(t, map[errors.Kind]int64{errors.K.IO: 1000}, snapshot)
+
+	// the snapshot is a copy
+	snapshot[errors.K.IO] = 0
+	require.Equal(t, int64(1000), counter.Snapshot()[errors.K.IO])
+}
@@ -3,14 +3,18 @@ package errors
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	stderrors "errors"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 )
 
 // populateStacktrace controls whether stacktraces are captured on error creation per default or not. This is
@@ -21,14 +25,63 @@ func init() {
 	SetPopulateStacktrace(true)
 }
 
+// SetPopulateStacktrace enables or disables the capture of stacktraces on error creation at runtime. When disabled,
+// E() and New() do no stack work at all and are as cheap as NoTrace() and NewNoTrace() - the only overhead is a single
+// atomic load.
+//
+// In contrast, the "errnostack" build tag disables stacktraces at compile time: it removes the stack information from
+// the Error struct altogether and turns all stack capturing and printing functions into no-ops.
 func SetPopulateStacktrace(b bool) {
 	populateStacktrace.Store(b)
 }
 
+// PopulateStacktrace returns true if stacktraces are captured on error creation, false otherwise. See
+// SetPopulateStacktrace.
 func PopulateStacktrace() bool {
 	return populateStacktrace.Load()
 }
 
+// stacktraceSampleRate holds the math.Float64bits of the stacktrace sample rate - see SetStacktraceSampleRate.
+var stacktraceSampleRate = atomic.Uint64{}
+
+func init() {
+	SetStacktraceSampleRate(1)
+}
+
+// SetStacktraceSampleRate sets the fraction of errors (between 0 and 1) for which E() and New() capture a stacktrace
+// if PopulateStacktrace() is true. The default of 1 captures a stacktrace for every error. Lower values reduce the cost
+// of error creation in hot paths while still providing the occasional stacktrace for diagnosis. A rate of 0 or less is
+// equivalent to SetPopulateStacktrace(false). The rate may be changed at runtime, concurrently with error creation.
+func SetStacktraceSampleRate(rate float64) {
+	stacktraceSampleRate.Store(math.Float64bits(rate))
+}
+
+// StacktraceSampleRate returns the fraction of errors for which a stacktrace is captured. See SetStacktraceSampleRate.
+func StacktraceSampleRate() float64 {
+	return math.Float64frombits(stacktraceSampleRate.Load())
+}
+
+// sampleSeed is the state of the PRNG used for stacktrace sampling.
+var sampleSeed uint64
+
+// sampleStacktrace reports whether a stacktrace should be captured according to StacktraceSampleRate().
+func sampleStacktrace() bool {
+	rate := StacktraceSampleRate()
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	// splitmix64: a cheap, lock-free PRNG that is good enough for sampling
+	z := atomic.AddUint64(&sampleSeed, 0x9e3779b97f4a7c15)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z = z ^ (z >> 31)
+	return float64(z>>11)/(1<<53) < rate
+}
+
 // PrintStacktrace controls whether stacktraces are printed per default or not.
 var PrintStacktrace = true
 
@@ -59,6 +112,66 @@ var PrintStacktrace = true
 //	runtime/asm_amd64.s:1357	goexit()
 var PrintStacktracePretty = true
 
+// KindBeforeOp controls the order of op and kind if they are not explicitly placed in DefaultFieldOrder (or the field
+// order passed to FormatError). By default, the op is printed before the kind:
+//
+//	op [read] kind [I/O error] file [a.txt]
+//
+// If enabled, the kind is printed first:
+//
+//	kind [I/O error] op [read] file [a.txt]
+var KindBeforeOp = false
+
+// StackPathFilter is an optional filter for the frames of printed stacktraces. It is called with the absolute path of
+// the source file of each frame and returns true if the frame should be removed, e.g. for frames from vendored or
+// third-party code:
+//
+//	errors.StackPathFilter = func(file string) bool {
+//		return strings.Contains(file, "/vendor/")
+//	}
+//
+// Runs of consecutive removed frames are collapsed into a single "... (N frames in vendored code)" line. The default
+// nil does not filter any frames.
+var StackPathFilter func(file string) bool
+
+// StackKeepModule is an optional module path prefix for the frames of printed stacktraces, e.g.
+//
+//	errors.StackKeepModule = "github.com/eluv-io/"
+//
+// If set, only frames whose package path starts with the given prefix are kept. Runs of consecutive removed frames,
+// e.g. from the runtime, the testing package or third-party modules, are collapsed into a single
+// "... (N frames in other modules)" line. In contrast to StackPathFilter, which removes selected frames, this keeps
+// only the frames of the application's own code. StackPathFilter is applied to the kept frames. The default "" does
+// not filter any frames.
+var StackKeepModule = ""
+
+// StackTrimPrefix is a prefix that is removed from the file path of each frame in printed and marshaled stacktraces,
+// e.g. a common module path:
+//
+//	errors.StackTrimPrefix = "github.com/eluv-io/"
+//	--> errors-go/stack_test.go:102 func1() instead of github.com/eluv-io/errors-go/stack_test.go:102 func1()
+//
+// The file paths are formatted as package path and file name. The default "" does not trim anything.
+var StackTrimPrefix = ""
+
+// StackSourceLines enables printing the source code line of the top frame of stacktraces beneath the frame:
+//
+//	github.com/eluv-io/errors-go/stack_test.go:102 func1()
+//	>>> return errors.E("op", errors.K.Invalid)
+//
+// Reading the source file is expensive, hence this is disabled per default and limited to the top frame. The line is
+// silently omitted if the source file is not available, e.g. in production deployments.
+var StackSourceLines = false
+
+// StackSourceFrame enables printing the source location set with Error.WithSource() as synthetic top frame of
+// stacktraces:
+//
+//	templates/user.tmpl:12 (source)
+//	github.com/eluv-io/errors-go/stack_test.go:102 func1()
+//
+// If multiple errors in the cause chain have a source location, the innermost one is used. Disabled by default.
+var StackSourceFrame = false
+
 // MarshalStacktrace controls whether stacktraces are marshaled to JSON or not. If enabled, an extra "stacktrace" field
 // is added to the error's JSON struct.
 var MarshalStacktrace = true
@@ -74,12 +187,28 @@ var MarshalStacktraceAsArray = true
 //
 // Trailing field keys after "" will be printed after any unreferenced fields.
 //
-// "op", "kind" and "cause" fields are specially treated if they don't appear in the order slice. "op" and "kind" will
-// be printed first among the unreferenced fields. "cause" will be printed last (even after all trailing fields).
-// Hence the default nil is equivalent to []string{"op", "kind", "", "cause"}
+// "op", "kind", "code" and "cause" fields are specially treated if they don't appear in the order slice. "op", "kind"
+// and "code" will be printed first among the unreferenced fields. "cause" will be printed last (even after all trailing
+// fields). Hence the default nil is equivalent to []string{"op", "kind", "code", "", "cause"}
+//
+// If OpKey, KindKey or CauseKey are changed, the field order has to reference the configured keys.
 var DefaultFieldOrder []string = nil
 
+// OpKey, KindKey and CauseKey are the keys of an Error's op, kind and cause. They are used in the Error's String and
+// JSON representations, when unmarshalling an Error from JSON, in DefaultFieldOrder and for field lookups with Field()
+// and GetField(). Change them to align the wire format with existing consumers, e.g. OpKey = "operation".
+//
+// The keys should be set once during program initialization and not be modified afterwards.
+var (
+	OpKey    = "op"
+	KindKey  = "kind"
+	CauseKey = "cause"
+)
+
 // Error is the type that implements the error interface and which is returned by E(), NoTrace(), etc.
+//
+// All methods may be called on a nil *Error: accessors return their zero value (Kind() returns K.Other), and the
+// With... functions are no-ops that return the nil receiver.
 type Error struct {
 	// the operation
 	op string
@@ -98,6 +227,12 @@ type Error struct {
 	ignoreStack bool
 	// the stacktrace from an unmarshalled error (if any)
 	unmarshalledStacktrace string
+	// skipTransform is set to true if OnCreateTransform should not be applied to this error
+	skipTransform bool
+	// ctxRef is an optional context for late field extraction, see WithContextRef()
+	ctxRef context.Context
+	// origin is the error this error was cloned from with Clone() (if any)
+	origin *Error
 }
 
 func (e *Error) Unwrap() error {
@@ -107,12 +242,40 @@ func (e *Error) Unwrap() error {
 	return e.cause
 }
 
-// MarshalJSON marshals this error as a JSON object
+// Is returns true if this error is a clone of the given target error - see Clone(). It allows errors.Is() to identify
+// a shared *Error (e.g. a package-level sentinel error) after it has been cloned in order to add fields.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if e == nil || !ok || t == nil {
+		return false
+	}
+	for o := e.origin; o != nil; o = o.origin {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON marshals this error as a JSON object. A nil error is marshaled as JSON null.
 func (e *Error) MarshalJSON() ([]byte, error) {
-	return e.marshalFields(true)
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return e.marshalFields(DefaultFieldOrder, true)
+}
+
+// MarshalJSONOrdered marshals this error as a JSON object like MarshalJSON, but orders the fields of this error and all
+// nested errors according to the given field order instead of the global DefaultFieldOrder. See DefaultFieldOrder for
+// the format of fieldOrder. A nil error is marshaled as JSON null.
+func (e *Error) MarshalJSONOrdered(fieldOrder []string) ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return e.marshalFields(fieldOrder, true)
 }
 
-func (e *Error) marshalFields(marshalStack bool) (res []byte, err error) {
+func (e *Error) marshalFields(fieldOrder []string, marshalStack bool) (res []byte, err error) {
 	b := &bytes.Buffer{}
 	needSep := false
 
@@ -129,15 +292,24 @@ func (e *Error) marshalFields(marshalStack bool) (res []byte, err error) {
 		b.Write(bts)
 		b.WriteByte(':')
 
-		if key == "cause" {
+		if key == CauseKey {
 			switch cause := val.(type) {
 			case *Error:
-				bts, err = cause.marshalFields(false)
+				bts, err = cause.marshalFields(fieldOrder, false)
 			default:
 				val, _ = convertForJSONMarshalling(cause)
 				bts, err = json.Marshal(val)
 			}
 		} else {
+			switch v := val.(type) {
+			case time.Time:
+				val = v.Format(time.RFC3339Nano)
+			case time.Duration:
+				val = v.String()
+			}
+			if TypedFields {
+				val = toTypedField(val)
+			}
 			bts, err = json.Marshal(val)
 		}
 
@@ -150,7 +322,7 @@ func (e *Error) marshalFields(marshalStack bool) (res []byte, err error) {
 
 	b.WriteByte('{')
 
-	err = e.writeFields(DefaultFieldOrder, kv)
+	err = e.writeFields(fieldOrder, kv)
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +345,65 @@ func (e *Error) marshalFields(marshalStack bool) (res []byte, err error) {
 	return b.Bytes(), nil
 }
 
+// JSONString returns the JSON representation of this error as produced by MarshalJSON(). It is intended for logging and
+// debugging. If marshaling fails, a JSON object with the marshaling error and the string representation of the error
+// is returned instead. Returns "null" if e is nil.
+func (e *Error) JSONString() string {
+	return JSONString(e)
+}
+
+// JSONStringIndent is like JSONString, but indents the JSON with the given indent string as json.MarshalIndent().
+func (e *Error) JSONStringIndent(indent string) string {
+	return jsonString(e, indent)
+}
+
+// JSONString returns the JSON representation of the given error. *Error and *ErrorList instances are marshaled as
+// usual, other errors as JSON string of their error message. See Error.JSONString() for details. Returns "null" if err is
+// nil.
+func JSONString(err error) string {
+	return jsonString(err, "")
+}
+
+func jsonString(err error, indent string) string {
+	var val interface{}
+	if err != nil {
+		val, _ = convertForJSONMarshalling(err)
+	}
+	var bts []byte
+	var merr error
+	if indent == "" {
+		bts, merr = json.Marshal(val)
+	} else {
+		bts, merr = json.MarshalIndent(val, "", indent)
+	}
+	if merr != nil {
+		bts, _ = json.Marshal(map[string]string{
+			"json_error": merr.Error(),
+			"error":      err.Error(),
+		})
+	}
+	return string(bts)
+}
+
+// MarshalText implements encoding.TextMarshaler and returns the string representation of the error without stacktrace
+// as returned by ErrorNoTrace().
+func (e *Error) MarshalText() ([]byte, error) {
+	return []byte(e.ErrorNoTrace()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler and parses the given text with Parse().
+func (e *Error) UnmarshalText(text []byte) error {
+	if e == nil {
+		return errNilUnmarshal
+	}
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*e = *parsed
+	return nil
+}
+
 func stacktraceToArray(s string) []string {
 	// trim empty lines or lines containing only whitespace
 	s = strings.Trim(s, "\n\t ")
@@ -188,7 +419,25 @@ func stacktraceToArray(s string) []string {
 }
 
 // UnmarshalJSON unmarshals the given JSON text, retaining the order of fields according to the JSON structure.
+// JSON null unmarshals into a zero Error. Any other JSON value that is not an object results in an error of kind
+// K.Invalid.
+//
+// A numeric "kind_code" field as emitted by non-Go systems is resolved with KindFromCode() and takes precedence over the
+// "kind" field. Unknown kind codes are retained as regular fields.
 func (e *Error) UnmarshalJSON(b []byte) error {
+	if e == nil {
+		return errNilUnmarshal
+	}
+	switch typ := jsonType(b); typ {
+	case "object", "":
+		// "" is invalid JSON: let the json decoder report the syntax error
+	case "null":
+		*e = Error{}
+		return nil
+	default:
+		return E("unmarshal", K.Invalid, "reason", "expected JSON object", "got", typ)
+	}
+
 	fields := make(map[orderedKey]valOrMap)
 	err := json.Unmarshal(b, &fields)
 	if err != nil {
@@ -198,6 +447,34 @@ func (e *Error) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// errNilUnmarshal is returned when unmarshaling into a nil *Error.
+var errNilUnmarshal = NoTrace("unmarshal", K.Invalid, "reason", "nil *Error")
+
+// jsonType returns the type of the top-level JSON value in b based on its first non-whitespace character: "object",
+// "array", "string", "number", "boolean" or "null". Returns "" if the type cannot be determined.
+func jsonType(b []byte) string {
+	b = bytes.TrimLeft(b, " \t\r\n")
+	if len(b) == 0 {
+		return ""
+	}
+	c := b[0]
+	switch {
+	case c == '{':
+		return "object"
+	case c == '[':
+		return "array"
+	case c == '"':
+		return "string"
+	case c == '-' || (c >= '0' && c <= '9'):
+		return "number"
+	case bytes.HasPrefix(b, []byte("true")) || bytes.HasPrefix(b, []byte("false")):
+		return "boolean"
+	case bytes.HasPrefix(b, []byte("null")):
+		return "null"
+	}
+	return ""
+}
+
 func (e *Error) unmarshalFrom(f map[orderedKey]valOrMap) {
 	keys := make(ordereKeys, 0, len(f))
 	for key := range f {
@@ -205,8 +482,15 @@ func (e *Error) unmarshalFrom(f map[orderedKey]valOrMap) {
 	}
 	sort.Sort(keys)
 	e.fields.Grow(len(f))
+	var codeKind Kind
 	for _, key := range keys {
 		val := f[key].Get()
+		if key.key == "kind_code" {
+			if k, ok := kindFromCodeVal(val); ok {
+				codeKind = k
+				continue
+			}
+		}
 		if key.key == "stacktrace" {
 			slice, ok := val.([]interface{})
 			if ok {
@@ -226,56 +510,329 @@ func (e *Error) unmarshalFrom(f map[orderedKey]valOrMap) {
 			_ = e.With(key.key, val)
 		}
 	}
+	if codeKind != "" {
+		// a known numeric kind code takes precedence over the kind string
+		e.kind = codeKind
+	}
+}
+
+// kindFromCodeVal resolves the unmarshalled value of a "kind_code" field to the corresponding kind. Returns false if the
+// value is not an integral number or not a known kind code.
+func kindFromCodeVal(val interface{}) (Kind, bool) {
+	var code int
+	switch v := val.(type) {
+	case float64:
+		code = int(v)
+		if float64(code) != v {
+			return "", false
+		}
+	case int:
+		code = v
+	case int64:
+		code = int(v)
+	default:
+		return "", false
+	}
+	return KindFromCode(code)
 }
 
 // Op returns the error's operation or "" if no op is set.
 func (e *Error) Op() string {
+	if e == nil {
+		return ""
+	}
 	return e.op
 }
 
 // Kind returns the error's kind or errors.K.Other if no kind is set.
 func (e *Error) Kind() Kind {
+	if e == nil {
+		return K.Other
+	}
 	return e.effectiveKind(K.Other)
 }
 
+// KindOr returns the error's kind, or the given fallback kind if the kind is K.Other, i.e. the error is unclassified.
+func (e *Error) KindOr(fallback Kind) Kind {
+	if kind := e.Kind(); kind != K.Other {
+		return kind
+	}
+	return fallback
+}
+
+// KindOr returns the result of calling the KindOr() method on the given err if it is an *Error. Returns fallback
+// otherwise.
+func KindOr(err error, fallback Kind) Kind {
+	e, ok := err.(*Error)
+	if !ok {
+		return fallback
+	}
+	return e.KindOr(fallback)
+}
+
 // Cause returns the error's cause or nil if no cause is set.
 func (e *Error) Cause() error {
+	if e == nil {
+		return nil
+	}
 	return e.cause
 }
 
+// UnderlyingCause returns the first meaningful error in the cause chain of this error. Purely structural wrappers -
+// *Errors without op, kind and fields that only carry a cause - are skipped. Returns nil if there is no cause or the
+// cause chain ends in a structural wrapper.
+//
+// In contrast, Cause() returns the immediate cause (which might be a structural wrapper), and GetRootCause() returns
+// the innermost error that is not an *Error.
+func (e *Error) UnderlyingCause() error {
+	if e == nil {
+		return nil
+	}
+	cause := e.cause
+	for {
+		c, ok := cause.(*Error)
+		if !ok {
+			return cause
+		}
+		if c == nil {
+			return nil
+		}
+		if !c.isWrapper() {
+			return c
+		}
+		cause = c.cause
+	}
+}
+
 // WithOp sets the given operation and returns this error instance for call chaining.
 func (e *Error) WithOp(op string) *Error {
-	if op != "" {
+	if e != nil && op != "" {
 		e.op = op
 	}
 	return e
 }
 
+// WithOpf sets the operation to fmt.Sprintf(format, args...) and returns this error instance for call chaining.
+func (e *Error) WithOpf(format string, args ...interface{}) *Error {
+	return e.WithOp(fmt.Sprintf(format, args...))
+}
+
 // WithKind sets the given kind and returns this error instance for call chaining.
 func (e *Error) WithKind(kind Kind) *Error {
-	if kind != "" {
+	if e != nil && kind != "" {
 		e.kind = kind
 	}
 	return e
 }
 
+// ReclassifyRoot returns a copy of this error in which the kind of the innermost *Error in the cause chain is set to the
+// given kind. The kind of outer errors that inherit their kind from the cause changes accordingly:
+//
+//	err := errors.E("fetch", errors.E("read", errors.K.IO, io.EOF))
+//	err.ReclassifyRoot(errors.K.NotExist).Kind() --> K.NotExist
+//	err.Kind()                                   --> K.IO
+//
+// The *Error instances of the cause chain are cloned, the error itself remains unchanged. Returns nil if e is nil.
+func (e *Error) ReclassifyRoot(kind Kind) *Error {
+	return e.ReclassifyWhere(func(ex *Error) bool {
+		cause, ok := ex.cause.(*Error)
+		return !ok || cause == nil
+	}, kind)
+}
+
+// ReclassifyWhere returns a copy of this error in which the kind of all *Error instances in the cause chain for which
+// the given predicate returns true is set to the given kind. The predicate is called for each level from the outermost
+// to the innermost error. Like ReclassifyRoot(), the cause chain is cloned and the error itself remains unchanged.
+// Returns e if e or pred is nil.
+func (e *Error) ReclassifyWhere(pred func(*Error) bool, kind Kind) *Error {
+	if e == nil || pred == nil {
+		return e
+	}
+	res := e.clone()
+	for ex := res; ; {
+		if pred(ex) {
+			_ = ex.WithKind(kind)
+		}
+		cause, ok := ex.cause.(*Error)
+		if !ok || cause == nil {
+			break
+		}
+		cause = cause.clone()
+		ex.cause = cause
+		ex = cause
+	}
+	return res
+}
+
+// AdoptCauseKind sets the kind of this error to the effective kind of its cause and returns this error instance for call
+// chaining. This makes a kind that would otherwise be inherited from the cause explicit, so that it is retained when
+// the cause is changed later, e.g. when replacing the cause with a sanitized error before returning it to a client:
+//
+//	err := errors.E("get user", errors.E("read", errors.K.NotExist, "path", p))
+//	err.AdoptCauseKind().WithCause(errors.Str("internal details removed"))
+//	--> op [get user] kind [item does not exist] cause [internal details removed]
+//
+// An explicitly set kind of this error is replaced. A default kind of this error is respected as in Kind(), i.e. it is
+// adopted if the cause is unclassified. Does nothing if the cause is not an *Error.
+func (e *Error) AdoptCauseKind() *Error {
+	if e == nil {
+		return e
+	}
+	if cause, ok := e.cause.(*Error); ok && cause != nil {
+		// resolve the kind that would be inherited without an explicit kind
+		e.kind = ""
+		e.kind = e.Kind()
+	}
+	return e
+}
+
 // WithDefaultKind sets the given kind as default and returns this error instance for call chaining. The default kind is
 // only used if the kind is not otherwise set e.g. with an explicit call to Error.Kind(kind) or by inheriting it from a
 // nested error. It's equivalent to calling Error.With(kind.Default()).
 func (e *Error) WithDefaultKind(kind Kind) *Error {
-	e.defaultKind = kind
+	if e != nil {
+		e.defaultKind = kind
+	}
+	return e
+}
+
+// WithCode sets the given application-specific error code and returns this error instance for call chaining. In
+// contrast to the coarse classification provided by the error's kind, the code is a fine-grained, machine-readable
+// identifier of the error condition, e.g. "ACCT_SUSPENDED". The code is stored in the "code" field.
+func (e *Error) WithCode(code string) *Error {
+	if e != nil && code != "" {
+		e.fields.Set("code", code)
+	}
+	return e
+}
+
+// WithSource sets the given source location and returns this error instance for call chaining. The location is stored
+// as "file:line" in the "source" field. It allows to annotate errors with a logical source location that the Go stack
+// cannot represent, e.g. the position in a template or other input to a code generator:
+//
+//	errors.E("render", errors.K.Invalid).WithSource("templates/user.tmpl", 12)
+//	--> op [render] kind [invalid] source [templates/user.tmpl:12]
+//
+// See StackSourceFrame for printing the location as part of the stacktrace.
+func (e *Error) WithSource(file string, line int) *Error {
+	if e == nil {
+		return e
+	}
+	e.fields.Set("source", file+":"+strconv.Itoa(line))
+	return e
+}
+
+// WithMessage sets a free-form, human-readable message and returns this error instance for call chaining. The message
+// complements the op and kind and is stored in the "message" field. It is included in Summary(). See Message().
+func (e *Error) WithMessage(msg string) *Error {
+	if e != nil && msg != "" {
+		e.fields.Set("message", msg)
+	}
+	return e
+}
+
+// WithRetryAfter sets the duration after which the failed operation may be retried, e.g. as indicated by a server's
+// Retry-After header, and returns this error instance for call chaining. The duration is stored in the "retry_after"
+// field as string (e.g. "30s") so that it survives a JSON round-trip. See RetryAfter().
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	if e != nil {
+		e.fields.Set("retry_after", d.String())
+	}
+	return e
+}
+
+// WithBytes adds the given binary value (e.g. a hash or nonce) as field and returns this error instance for call
+// chaining. The value is copied. Like any []byte field value, it is rendered as hex string in Error() and as base64
+// string in JSON.
+func (e *Error) WithBytes(key string, b []byte) *Error {
+	if e != nil {
+		e.fields.Set(key, append([]byte(nil), b...))
+	}
+	return e
+}
+
+// WithTime adds the given time as field and returns this error instance for call chaining. Like any time.Time field
+// value, it is rendered in RFC3339 format with nanoseconds (time.RFC3339Nano) in Error() and in JSON.
+func (e *Error) WithTime(key string, t time.Time) *Error {
+	if e != nil {
+		e.fields.Set(key, t)
+	}
+	return e
+}
+
+// WithTimestamp sets the given time in the "timestamp" field and returns this error instance for call chaining. The
+// timestamp records when the error occurred and is marshaled to JSON as RFC3339Nano string, so that it is retained when
+// the error is sent to another process. See TransitTime().
+func (e *Error) WithTimestamp(t time.Time) *Error {
+	return e.WithTime("timestamp", t)
+}
+
+// WithDuration adds the given duration as field and returns this error instance for call chaining. Like any
+// time.Duration field value, it is rendered with its String() method in Error() and in JSON, e.g. "1m30s".
+func (e *Error) WithDuration(key string, d time.Duration) *Error {
+	if e != nil {
+		e.fields.Set(key, d)
+	}
 	return e
 }
 
 // WithCause sets the given original error and returns this error instance for call chaining. If the cause is an *Error
 // and this error's kind is not yet initialized, it inherits the kind of the cause.
+//
+// If this error is already part of the cause chain of err, setting the cause would create a reference cycle. In that
+// case, the cause is not set and the field "cycle_detected" is set to true instead.
+//
+// A nil cause is ignored, including a nil *Error or *ErrorList.
 func (e *Error) WithCause(err error) *Error {
-	if err != nil {
+	if e != nil && !isNil(err) {
+		if e.inChain(err) {
+			e.fields.Set("cycle_detected", true)
+			return e
+		}
 		e.cause = err
 	}
 	return e
 }
 
+// WithCauseString sets an error created with Str(msg) as cause and returns this error instance for call chaining. It is
+// intended for failures that are only available as message, e.g. from an external system. An empty message is
+// ignored.
+func (e *Error) WithCauseString(msg string) *Error {
+	if msg == "" {
+		return e
+	}
+	return e.WithCause(Str(msg))
+}
+
+// inChain returns true if this error is err itself or is contained in the tree of errors obtained by repeatedly
+// unwrapping err, including all members of an *ErrorList and of errors implementing Unwrap() []error.
+func (e *Error) inChain(err error) bool {
+	for err != nil {
+		if ex, ok := err.(*Error); ok && ex == e {
+			return true
+		}
+		var errs []error
+		switch t := err.(type) {
+		case *ErrorList:
+			if t != nil {
+				errs = t.Errors
+			}
+		case interface{ Unwrap() []error }:
+			errs = t.Unwrap()
+		default:
+			err = stderrors.Unwrap(err)
+			continue
+		}
+		for _, ex := range errs {
+			if e.inChain(ex) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
 // With adds additional context information in the form of key value pairs and returns this error instance for call
 // chaining.
 func (e *Error) With(args ...interface{}) *Error {
@@ -289,7 +846,7 @@ func (e *Error) With(args ...interface{}) *Error {
 			argc = len(slice)
 		}
 	}
-	if argc == 0 {
+	if argc == 0 || e == nil {
 		return e
 	}
 
@@ -308,6 +865,9 @@ func (e *Error) With(args ...interface{}) *Error {
 		case DefaultKind:
 			_ = e.WithDefaultKind(Kind(a))
 			continue
+		case skipTransform:
+			e.skipTransform = true
+			continue
 		case error:
 			_ = e.WithCause(a)
 			continue
@@ -320,19 +880,19 @@ func (e *Error) With(args ...interface{}) *Error {
 			idx++
 
 			switch key {
-			case "op":
+			case OpKey:
 				if op, ok := val.(string); ok {
 					_ = e.WithOp(op)
 				}
 				continue
-			case "kind":
+			case KindKey:
 				knd, ok := val.(Kind)
 				if !ok {
 					knd = Kind(toString(val))
 				}
 				_ = e.WithKind(knd)
 				continue
-			case "cause":
+			case CauseKey:
 				if val == nil {
 					continue
 				}
@@ -354,53 +914,320 @@ func (e *Error) With(args ...interface{}) *Error {
 	return e
 }
 
-func (e *Error) isZero() bool {
-	return e.op == "" && e.kind == "" && e.cause == nil && len(e.fields) == 0
+// WithIf adds the given key value pairs like With() if cond is true and returns this error instance for call chaining:
+//
+//	errors.E("op").WithIf(verbose, "detail", d)
+func (e *Error) WithIf(cond bool, args ...interface{}) *Error {
+	if !cond {
+		return e
+	}
+	return e.With(args...)
 }
 
-func (e *Error) field(key string) (interface{}, bool) {
-	switch key {
-	case "op":
-		if e.op != "" {
-			return e.op, true
-		}
-		return nil, false
-	case "kind":
-		return e.Kind(), true
-	case "cause":
-		if e.cause != nil {
-			return e.cause, true
-		}
-		return nil, false
+// WithUnless adds the given key value pairs like With() if cond is false and returns this error instance for call
+// chaining. See WithIf().
+func (e *Error) WithUnless(cond bool, args ...interface{}) *Error {
+	return e.WithIf(!cond, args...)
+}
+
+// Tap calls fn with this error for side effects like logging or counting and returns this error instance unchanged for
+// call chaining:
+//
+//	errors.E("op", err).Tap(countError).With("key", "val")
+//
+// fn is not called if e or fn is nil.
+func (e *Error) Tap(fn func(*Error)) *Error {
+	if e != nil && fn != nil {
+		fn(e)
 	}
-	return e.fields.Get(key)
+	return e
 }
 
-// Field returns the given field from this error or any nested errors. Returns nil if the field does not exist.
-func (e *Error) Field(key string) interface{} {
-	var err interface{} = e
-	for {
-		ex, ok := err.(*Error)
-		if !ok {
-			break
-		}
-		val, ok := ex.field(key)
-		if ok {
-			return val
-		}
-		err = ex.cause
+// IsZero returns true if this error carries no information at all: op, kind, cause and fields are all empty. This is
+// the case for example for an error created with E() without arguments. Returns true if e is nil.
+func (e *Error) IsZero() bool {
+	return e == nil || e.isWrapper() && e.cause == nil
+}
+
+// OrNil returns this error as error interface, or an untyped nil if e is nil or IsZero() returns true. It is the safe way
+// to return a possibly nil *Error as error, since a nil *Error assigned to an error interface is not nil:
+//
+//	func check(ctx context.Context) error {
+//		return errors.FromContext(ctx, "check").OrNil() // instead of return errors.FromContext(ctx, "check")
+//	}
+func (e *Error) OrNil() error {
+	if e.IsZero() {
+		return nil
 	}
-	return nil
+	return e
 }
 
-// GetField attempts to retrieve the field with the given key in this Error and returns its value converted to a string
-// with fmt.Sprint(val). If the field doesn't exist, it tries to find it (recursively) in the 'cause' of the error.
-// Returns the retrieved field value and true if found, or the empty string and false if not found.
-func (e *Error) GetField(key string) (string, bool) {
-	var err interface{} = e
-	for {
-		ex, ok := err.(*Error)
-		if !ok {
+// Return returns the given error as error interface, or an untyped nil if e is nil or IsZero() returns true. It is the
+// function form of Error.OrNil() and is convenient for returning the result of functions that return an *Error, like
+// Wrap() or FromContext(), from a function that returns an error:
+//
+//	func read(ctx context.Context, r io.Reader) error {
+//		...
+//		return errors.Return(errors.Wrap(err, "op", "read"))
+//	}
+func Return(e *Error) error {
+	return e.OrNil()
+}
+
+// IsEmpty returns true if the given error carries no real error information, i.e. if it is
+//   - nil or NilError
+//   - an *Error for which IsZero() returns true
+//   - an *ErrorList without errors
+func IsEmpty(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return true
+	case *nilError:
+		return true
+	case *Error:
+		return e.IsZero()
+	case *ErrorList:
+		return e == nil || len(e.Errors) == 0
+	}
+	return false
+}
+
+// Depth returns the number of *Error instances in the cause chain of this error, including the error itself: 1 for an
+// error without nested *Error, 2 for an error whose cause is a leaf *Error, and so on. Causes that are not an *Error are
+// not counted. Returns 0 if e is nil.
+func (e *Error) Depth() int {
+	depth := 0
+	for ex := e; ex != nil; {
+		depth++
+		ex, _ = ex.cause.(*Error)
+	}
+	return depth
+}
+
+// Depth returns the result of calling the Depth() method on the given err if it is an *Error. Returns 0 otherwise.
+func Depth(err error) int {
+	e, ok := err.(*Error)
+	if !ok {
+		return 0
+	}
+	return e.Depth()
+}
+
+// Chain returns the cause chain of this error as a slice, starting with the error itself and ending with the root
+// cause, i.e. the first cause that is not an *Error (if any):
+//
+//	errors.E("fetch", errors.E("read", io.EOF)).Chain() --> [op [fetch]..., op [read]..., EOF]
+//
+// Returns nil if e is nil. See also the package-level Chain() function, which also traverses errors of other types.
+func (e *Error) Chain() []error {
+	var res []error
+	for ex := e; ex != nil; {
+		res = append(res, ex)
+		cause, ok := ex.cause.(*Error)
+		if !ok {
+			if ex.cause != nil {
+				res = append(res, ex.cause)
+			}
+			break
+		}
+		ex = cause
+	}
+	return res
+}
+
+// Chain returns the chain of errors obtained by repeatedly unwrapping the given error with errors.Unwrap() from the
+// standard library, starting with err itself. In contrast to Error.Chain(), it traverses errors of any type that
+// implement Unwrap() error, including errors created with fmt.Errorf("... %w", err). Returns nil if err is nil.
+func Chain(err error) []error {
+	var res []error
+	for err != nil {
+		if e, ok := err.(*Error); ok && e == nil {
+			break
+		}
+		res = append(res, err)
+		err = stderrors.Unwrap(err)
+	}
+	return res
+}
+
+// isWrapper returns true if this error carries no information of its own (op, kind or fields) and at most a cause.
+func (e *Error) isWrapper() bool {
+	return e.op == "" && e.kind == "" && len(e.fields) == 0
+}
+
+func (e *Error) field(key string) (interface{}, bool) {
+	switch key {
+	case OpKey:
+		if e.op != "" {
+			return e.op, true
+		}
+		return nil, false
+	case KindKey:
+		return e.Kind(), true
+	case CauseKey:
+		if e.cause != nil {
+			return e.cause, true
+		}
+		return nil, false
+	}
+	return e.fields.Get(key)
+}
+
+// Field returns the given field from this error or any nested errors. Returns nil if the field does not exist.
+func (e *Error) Field(key string) interface{} {
+	var err interface{} = e
+	for {
+		ex, ok := err.(*Error)
+		if !ok || ex == nil {
+			break
+		}
+		val, ok := ex.field(key)
+		if ok {
+			return val
+		}
+		err = ex.cause
+	}
+	return nil
+}
+
+// FieldDeep returns the given field from the innermost error in the chain of nested errors that defines it. In contrast
+// to Field, which returns the first (outermost) occurrence, FieldDeep continues the traversal towards the root cause and
+// returns the last occurrence. Returns nil if the field does not exist.
+func (e *Error) FieldDeep(key string) interface{} {
+	var res interface{}
+	var err interface{} = e
+	for {
+		ex, ok := err.(*Error)
+		if !ok || ex == nil {
+			break
+		}
+		val, ok := ex.field(key)
+		if ok {
+			res = val
+		}
+		err = ex.cause
+	}
+	return res
+}
+
+// FieldAll returns the values of the given field from this error and all nested errors that define it, outermost
+// first. Returns nil if the field does not exist.
+func (e *Error) FieldAll(key string) []interface{} {
+	var res []interface{}
+	var err interface{} = e
+	for {
+		ex, ok := err.(*Error)
+		if !ok || ex == nil {
+			break
+		}
+		val, ok := ex.field(key)
+		if ok {
+			res = append(res, val)
+		}
+		err = ex.cause
+	}
+	return res
+}
+
+// Fields returns the op, kind, fields and cause of this error as flat list of key-value pairs. The pairs are ordered
+// according to DefaultFieldOrder, i.e. in the same order as in the string representation returned by Error():
+//
+//	errors.E("read", errors.K.IO, io.EOF, "file", f).Fields()
+//	--> []interface{}{"op", "read", "kind", errors.K.IO, "file", f, "cause", io.EOF}
+//
+// Returns nil if e is nil.
+func (e *Error) Fields() []interface{} {
+	if e == nil {
+		return nil
+	}
+	res := make([]interface{}, 0, len(e.fields)+6)
+	_ = e.writeFields(DefaultFieldOrder, func(key interface{}, val interface{}) error {
+		res = append(res, key, val)
+		return nil
+	})
+	return res
+}
+
+// NumFields returns the number of key-value fields of this error, excluding op, kind, cause and stacktrace. Fields of
+// nested errors are not counted. Returns 0 if e is nil.
+func (e *Error) NumFields() int {
+	return len(e.Keys())
+}
+
+// Keys returns the keys of the key-value fields of this error in the order they were added, excluding op, kind, cause
+// and stacktrace. Fields of nested errors are not included. Returns nil if e is nil or has no fields.
+func (e *Error) Keys() []string {
+	if e == nil {
+		return nil
+	}
+	var res []string
+	for i := 0; i+1 < len(e.fields); i += 2 {
+		if key := toString(e.fields[i]); key != "stacktrace" {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// AllFields returns the op, kind and fields of this error and all nested errors as a single flat map. The keys of
+// nested errors are prefixed with the path to the nested error in order to avoid collisions, e.g.
+//
+//	errors.E("get user", errors.K.NotExist, errors.E("read", io.EOF, "file", f), "user", u).AllFields()
+//	--> map[string]interface{}{
+//		"op":          "get user",
+//		"kind":        errors.K.NotExist,
+//		"user":        u,
+//		"cause.op":    "read",
+//		"cause.kind":  errors.K.Other,
+//		"cause.file":  f,
+//		"cause.cause": "EOF",
+//	}
+//
+// A cause that is not an *Error is added with its error message. Returns nil if e is nil.
+func (e *Error) AllFields() map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+	res := make(map[string]interface{})
+	e.walkAllFields(func(prefix, key string, val interface{}) {
+		res[prefix+key] = val
+	})
+	return res
+}
+
+// walkAllFields calls fn for the op, kind and fields of this error and all nested errors as described in AllFields().
+// The key of a field of a nested error is passed separately from the prefix of that error, e.g. "cause.cause." and
+// "op".
+func (e *Error) walkAllFields(fn func(prefix, key string, val interface{})) {
+	prefix := ""
+	for ex := e; ex != nil; {
+		if ex.op != "" {
+			fn(prefix, OpKey, ex.op)
+		}
+		fn(prefix, KindKey, ex.Kind())
+		for i := 0; i+1 < len(ex.fields); i += 2 {
+			fn(prefix, toString(ex.fields[i]), ex.fields[i+1])
+		}
+		if ex.cause == nil {
+			break
+		}
+		cause, ok := ex.cause.(*Error)
+		if !ok {
+			fn(prefix, CauseKey, ex.cause.Error())
+		}
+		prefix += CauseKey + "."
+		ex = cause
+	}
+}
+
+// GetField attempts to retrieve the field with the given key in this Error and returns its value converted to a string
+// with fmt.Sprint(val). If the field doesn't exist, it tries to find it (recursively) in the 'cause' of the error.
+// Returns the retrieved field value and true if found, or the empty string and false if not found.
+func (e *Error) GetField(key string) (string, bool) {
+	var err interface{} = e
+	for {
+		ex, ok := err.(*Error)
+		if !ok || ex == nil {
 			break
 		}
 		val, ok := ex.field(key)
@@ -431,10 +1258,105 @@ func Field(err error, key string) interface{} {
 	return e.Field(key)
 }
 
+// FieldDeep returns the result of calling the FieldDeep() method on the given err if it is an *Error. Returns nil
+// otherwise.
+func FieldDeep(err error, key string) interface{} {
+	e, ok := err.(*Error)
+	if !ok {
+		return nil
+	}
+	return e.FieldDeep(key)
+}
+
+// FieldAll returns the result of calling the FieldAll() method on the given err if it is an *Error. Returns nil
+// otherwise.
+func FieldAll(err error, key string) []interface{} {
+	e, ok := err.(*Error)
+	if !ok {
+		return nil
+	}
+	return e.FieldAll(key)
+}
+
 // Separator is the string used to separate nested errors. By default, nested errors
 // are indented on a new line.
 var Separator = ":\n\t"
 
+// NestedIndent is the indentation unit for nested errors. If set, nested errors are printed on a new line and indented
+// progressively by one unit per nesting level, and Separator is not used:
+//
+//	errors.NestedIndent = "  "
+//	--> op [fetch] kind [I/O error] cause:
+//	      op [read] kind [I/O error] cause:
+//	        op [open] kind [I/O error] cause [EOF]
+//
+// If empty (the default), all nested errors are separated with Separator.
+var NestedIndent = ""
+
+// ElideRepeatedKind controls whether the kind of a nested error is omitted in the string representation of an error if
+// it is the same as the kind of its parent error:
+//
+//	errors.ElideRepeatedKind = true
+//	--> op [fetch] kind [I/O error] cause:
+//		op [read] cause:
+//		op [open] cause [EOF]
+//
+// It affects only Error(), ErrorNoTrace() and FormatError(), not the JSON representation or error matching.
+var ElideRepeatedKind = false
+
+// InlineCauseList controls how a cause that is an *ErrorList is printed in the string representation of an error. If
+// enabled, the errors of the list are printed on separate, indented lines beneath the cause, in the same format as
+// ErrorList.Error() but without stacktraces:
+//
+//	errors.InlineCauseList = true
+//	--> op [batch] kind [I/O error] cause:
+//		error-list count [2]
+//			0: op [read] kind [I/O error] cause [EOF]
+//			1: unexpected EOF
+//
+// Lists with a single error are not affected. If disabled (the default), the list is printed like any other non-*Error
+// cause.
+var InlineCauseList = false
+
+// ColorOutput controls whether the kind is colored with ANSI escape sequences according to KindColors in the string
+// representation of an error. It is intended for interactive output on a terminal and should not be enabled for logs
+// or any other non-interactive output. Only Error(), ErrorNoTrace() and FormatError() are affected.
+var ColorOutput = false
+
+// KindColors maps kinds to the ANSI escape sequences used to color them if ColorOutput is enabled. Kinds that are not
+// in the map are colored red.
+var KindColors = map[Kind]string{
+	K.Warn: "\x1b[33m", // yellow
+}
+
+// colorKind returns the given kind wrapped in the ANSI escape sequences of its color.
+func colorKind(k Kind) string {
+	color, ok := KindColors[k]
+	if !ok {
+		color = "\x1b[31m" // red
+	}
+	return color + string(k) + "\x1b[0m"
+}
+
+// nestedSeparator returns the separator for a nested error at the given depth (1 for the direct cause of the outermost
+// error).
+func nestedSeparator(depth int) string {
+	if NestedIndent == "" {
+		return Separator
+	}
+	return ":\n" + strings.Repeat(NestedIndent, depth)
+}
+
+// MissingValuePlaceholder is the value stored for a key that is passed to E() or With() without a value, e.g. the key
+// "file" in E("read", "file"). Change it to a sentinel that cannot collide with real field values if necessary.
+var MissingValuePlaceholder = "<missing>"
+
+// StrictArgs enables a development-mode check of the arguments passed to E() and its variants: if the first argument is
+// neither a string (the op), a Kind, a DefaultKind nor an error, it is most likely the result of wrong argument order,
+// e.g. E(42, "file", f). In that case, the type of the offending argument is recorded in the "invalid_leading_arg"
+// field. The argument itself is still processed as usual, i.e. as key of a key-value pair. Disabled by default.
+var StrictArgs = false
+
 // E creates a new error initialized with the given (optional) operation, kind, cause and key-value fields. All
 // arguments are optional, but if provided they have to be specified in that order.
 //
@@ -453,17 +1375,66 @@ var Separator = ":\n\t"
 //	errors.E("download", errors.K.IO, err, "file", f, "user", usr) --> same as errors.E()...With("file", f).With("user", usr)
 //	errors.E(errors.K.NotExist, "file", f) --> same as errors.E().WithKind(errors.K.NotExist).With("file", f)
 func E(args ...interface{}) *Error {
-	e := NoTrace(args...)
+	return created(newErrorWithStack(args))
+}
 
-	if PopulateStacktrace() {
-		e.populateStack()
+// NoTrace is the same as E, but does not populate a stack trace. Use in cases where the stacktrace is not desired.
+func NoTrace(args ...interface{}) *Error {
+	return created(newError(args))
+}
+
+// Ef creates a new error with the operation set to fmt.Sprintf(format, args...). Kind, cause and additional fields can
+// be set with the error's With functions:
+//
+//	errors.Ef("load chunk %d", n).WithKind(errors.K.IO).WithCause(err)
+func Ef(format string, args ...interface{}) *Error {
+	return E(fmt.Sprintf(format, args...)).dropStackFrames(1)
+}
+
+// Errorf creates a new error in the style of fmt.Errorf() in order to ease migration from the standard library. The
+// error message fmt.Sprintf(format, args...) is stored in the "message" field, and the op is left empty. If the format
+// contains a %w verb, the corresponding argument becomes the cause of the error, and its kind is inherited if it is an
+// *Error:
+//
+//	errors.Errorf("load config %s: %w", f, err)
+//	--> kind [I/O error] message [load config a.txt: EOF] cause [EOF]
+//
+// *Error operands are formatted with ErrorNoTrace() in the message. At most one %w verb is supported: if the format
+// contains multiple %w verbs, an error of kind K.Invalid with the format is returned instead.
+func Errorf(format string, args ...interface{}) *Error {
+	if strings.Count(strings.ReplaceAll(format, "%%", ""), "%w") > 1 {
+		return E("errorf", K.Invalid, "reason", "multiple %w verbs", "format", format).dropStackFrames(1)
+	}
+	// format *Error operands without stacktrace
+	fmtArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		if e, ok := arg.(*Error); ok && e != nil {
+			arg = noTraceError{e}
+		}
+		fmtArgs[i] = arg
 	}
+	wrapped := fmt.Errorf(format, fmtArgs...)
+	var cause error
+	if u, ok := wrapped.(interface{ Unwrap() error }); ok {
+		cause = u.Unwrap()
+		if nt, ok := cause.(noTraceError); ok {
+			cause = nt.e
+		}
+	}
+	return E(cause, "message", wrapped.Error()).dropStackFrames(1)
+}
 
-	return e
+// noTraceError formats the wrapped *Error without stacktrace.
+type noTraceError struct {
+	e *Error
 }
 
-// NoTrace is the same as E, but does not populate a stack trace. Use in cases where the stacktrace is not desired.
-func NoTrace(args ...interface{}) *Error {
+func (n noTraceError) Error() string {
+	return n.e.ErrorNoTrace()
+}
+
+// newError creates a new error from the given args as described in E.
+func newError(args []interface{}) *Error {
 	e := &Error{}
 	argc := len(args)
 
@@ -476,19 +1447,52 @@ func NoTrace(args ...interface{}) *Error {
 		}
 	}
 
+	var invalidArg interface{}
 	if len(args) > 0 {
 		if op, ok := args[0].(string); ok {
 			// the first arg is a string - use it as op
 			_ = e.WithOp(op)
 			args = args[1:]
+		} else if StrictArgs && !isLeadingArg(args[0]) {
+			invalidArg = args[0]
 		}
 	}
 
 	_ = e.With(args...)
 
+	if invalidArg != nil {
+		e.fields.Set("invalid_leading_arg", fmt.Sprintf("%T", invalidArg))
+	}
+
+	return e
+}
+
+// newErrorWithStack creates a new error from the given args like E, but does not invoke the creation hooks. This allows
+// callers to complete the error before calling created(). The stacktrace - if populated - starts with the caller of the
+// function calling newErrorWithStack.
+func newErrorWithStack(args []interface{}) *Error {
+	e := newError(args)
+
+	if PopulateStacktrace() && sampleStacktrace() {
+		// drops the newErrorWithStack() and populateStack() functions
+		e.populateStack()
+		// drops the function calling newErrorWithStack(), e.g. E()
+		_ = e.dropStackFrames(1)
+	}
+
 	return e
 }
 
+// isLeadingArg returns true if the given argument is valid as first argument of E(): a Kind, a DefaultKind, an error
+// or nil. Strings are handled separately as op.
+func isLeadingArg(arg interface{}) bool {
+	switch arg.(type) {
+	case nil, Kind, DefaultKind, skipTransform, error:
+		return true
+	}
+	return false
+}
+
 // Template returns a function that creates a base error with an initial set of fields. When called, additional fields
 // can be passed that complement the error template:
 //
@@ -526,9 +1530,10 @@ func TNoTrace(fields ...interface{}) TemplateFn {
 
 type TemplateFn func(fields ...interface{}) *Error
 
-// IfNotNil returns an error based on this template iff 'err' is not nil. Otherwise returns nil.
+// IfNotNil returns an error based on this template iff 'err' is not nil. Otherwise returns nil. A nil *Error or
+// *ErrorList is considered nil.
 func (t TemplateFn) IfNotNil(err error, fields ...interface{}) error {
-	if err == nil {
+	if isNil(err) {
 		return nil
 	}
 	return t(append(fields, err)...).dropStackFrames(1)
@@ -571,6 +1576,13 @@ func (e *Error) ErrorNoTrace() string {
 }
 
 func (e *Error) toString(printStacktrace bool, fieldOrder ...string) string {
+	return e.format(printStacktrace, 0, "", fieldOrder)
+}
+
+// format converts this error to a string. depth is the nesting level of this error in the cause chain of the outermost
+// error and determines the indentation of nested errors if NestedIndent is set. parentKind is the effective kind of
+// the error that has this error as cause, or "" for the outermost error - see ElideRepeatedKind.
+func (e *Error) format(printStacktrace bool, depth int, parentKind Kind, fieldOrder []string) string {
 	if e == nil {
 		return ""
 	}
@@ -581,7 +1593,13 @@ func (e *Error) toString(printStacktrace bool, fieldOrder ...string) string {
 		fieldOrder = DefaultFieldOrder
 	}
 	_ = e.writeFields(fieldOrder, func(key interface{}, val interface{}) error {
-		e.writeKeyVal(b, key, val)
+		if key == KindKey && ElideRepeatedKind && parentKind != "" && val == parentKind {
+			return nil
+		}
+		if key == KindKey && ColorOutput {
+			val = colorKind(val.(Kind))
+		}
+		e.writeKeyVal(b, key, val, depth)
 		return nil
 	})
 
@@ -614,26 +1632,49 @@ func (e *Error) writeFields(fieldOrder []string, writeKV func(key interface{}, v
 			return
 		}
 		printOthers = false
-		if e.op != "" && unreferenced("op") {
-			err = writeKV("op", e.op)
+		writeOp := func() error {
+			if e.op != "" && unreferenced(OpKey) {
+				return writeKV(OpKey, e.op)
+			}
+			return nil
 		}
-		if unreferenced("kind") {
-			err = writeKV("kind", e.Kind())
+		writeKind := func() error {
+			if unreferenced(KindKey) {
+				return writeKV(KindKey, e.Kind())
+			}
+			return nil
+		}
+		if KindBeforeOp {
+			err = writeKind()
+			if err == nil {
+				err = writeOp()
+			}
+		} else {
+			err = writeOp()
+			if err == nil {
+				err = writeKind()
+			}
 		}
 		if err != nil {
 			return err
 		}
+		if code, ok := e.fields.Get("code"); ok && unreferenced("code") {
+			err = writeKV("code", code)
+			if err != nil {
+				return err
+			}
+		}
 		for i := 0; i+1 < len(e.fields); i += 2 {
 			key := e.fields[i]
-			if unreferenced(key) {
+			if key != "code" && unreferenced(key) {
 				err = writeKV(key, e.fields[i+1])
 				if err != nil {
 					return err
 				}
 			}
 		}
-		if e.cause != nil && unreferenced("cause") {
-			err = writeKV("cause", e.cause)
+		if e.cause != nil && unreferenced(CauseKey) {
+			err = writeKV(CauseKey, e.cause)
 			if err != nil {
 				return err
 			}
@@ -655,30 +1696,69 @@ func (e *Error) writeFields(fieldOrder []string, writeKV func(key interface{}, v
 	return printOtherFields()
 }
 
-func (e *Error) writeKeyVal(b *bytes.Buffer, key interface{}, val interface{}) {
-	if key == "cause" {
+func (e *Error) writeKeyVal(b *bytes.Buffer, key interface{}, val interface{}, depth int) {
+	if key == CauseKey {
 		if cause, ok := e.cause.(*Error); ok {
-			if !cause.isZero() {
+			if !cause.IsZero() {
 				pad(b, " ")
-				b.WriteString("cause")
-				b.WriteString(Separator)
-				b.WriteString(cause.toString(false))
+				b.WriteString(CauseKey)
+				b.WriteString(nestedSeparator(depth + 1))
+				b.WriteString(cause.format(false, depth+1, e.Kind(), nil))
 			}
 			return
 		}
+		if list, ok := e.cause.(*ErrorList); ok && InlineCauseList && list != nil && len(list.Errors) > 1 {
+			e.writeCauseList(b, list, depth)
+			return
+		}
 	}
 	pad(b, " ")
 	b.WriteString(key.(string))
 	b.WriteString(" [")
-	b.WriteString(fmt.Sprint(val))
+	switch v := val.(type) {
+	case []byte:
+		b.WriteString(hex.EncodeToString(v))
+	case time.Time:
+		b.WriteString(v.Format(time.RFC3339Nano))
+	default:
+		b.WriteString(fmt.Sprint(val))
+	}
+	b.WriteString("]")
+}
+
+// writeCauseList writes the given error list cause as described in InlineCauseList.
+func (e *Error) writeCauseList(b *bytes.Buffer, list *ErrorList, depth int) {
+	indent := NestedIndent
+	if indent == "" {
+		indent = "\t"
+	}
+	indent = strings.Repeat(indent, depth+2)
+
+	pad(b, " ")
+	b.WriteString(CauseKey)
+	b.WriteString(nestedSeparator(depth + 1))
+	b.WriteString("error-list count [")
+	b.WriteString(strconv.Itoa(len(list.Errors)))
 	b.WriteString("]")
+	for idx, err := range list.Errors {
+		b.WriteString("\n")
+		b.WriteString(indent)
+		b.WriteString(strconv.Itoa(idx))
+		b.WriteString(": ")
+		if ex, ok := err.(*Error); ok {
+			b.WriteString(ex.format(false, depth+2, e.Kind(), nil))
+		} else {
+			b.WriteString(err.Error())
+		}
+	}
 }
 
 // ClearStacktrace creates a copy of this error and removes the stacktrace from it and all nested causes.
 func (e *Error) ClearStacktrace() *Error {
-	clone := *e
-	clone.fields = make([]interface{}, len(e.fields))
-	copy(clone.fields, e.fields)
+	if e == nil {
+		return nil
+	}
+	clone := e.clone()
 
 	clone.clearStack()
 	clone.fields.Delete("stacktrace")
@@ -687,6 +1767,103 @@ func (e *Error) ClearStacktrace() *Error {
 	if e2, ok := clone.cause.(*Error); ok {
 		clone.cause = e2.ClearStacktrace()
 	}
+	return clone
+}
+
+// Simplify creates a copy of this error and all nested causes, removing nested errors that carry no information of
+// their own - i.e. no op, kind or fields - and merely wrap another error. If such an error wraps a non-*Error cause,
+// the cause is lifted into the parent error. An empty nested error without cause is removed as well. Errors that have
+// an op, kind or fields are retained. This error itself is always retained:
+//
+//	errors.E("get user", errors.E(errors.E(errors.E("read", errors.K.IO)))).Simplify()
+//	--> same as errors.E("get user", errors.E("read", errors.K.IO))
+//	errors.E("get user", errors.E(errors.E(io.EOF))).Simplify()
+//	--> same as errors.E("get user", io.EOF)
+//
+// Returns nil if e is nil.
+func (e *Error) Simplify() *Error {
+	if e == nil {
+		return nil
+	}
+	clone := e.clone()
+
+	if cause, ok := clone.cause.(*Error); ok && cause != nil {
+		for cause.isWrapper() && cause.defaultKind == "" {
+			next, ok := cause.cause.(*Error)
+			if !ok || next == nil {
+				break
+			}
+			cause = next
+		}
+		if cause.isWrapper() && cause.defaultKind == "" {
+			// an empty leaf is removed, and a wrapper of a non-*Error cause is replaced by that cause
+			clone.cause = cause.cause
+		} else {
+			clone.cause = cause.Simplify()
+		}
+	}
+	return clone
+}
+
+// ReplaceField creates a copy of this error and all nested causes, replacing the value of the field with the given key
+// by newVal in every error of the cause chain that has that field. Errors that do not have the field are copied
+// unchanged. The op, kind and cause of the errors are not affected.
+//
+// ReplaceField is useful for redacting sensitive information or rewriting environment-specific values (e.g. file
+// paths) before an error is shipped to a third party:
+//
+//	err = err.ReplaceField("path", "<redacted>")
+func (e *Error) ReplaceField(key string, newVal interface{}) *Error {
+	if e == nil {
+		return nil
+	}
+
+	clone := e.clone()
+	if _, ok := clone.fields.Get(key); ok {
+		clone.fields.Set(key, newVal)
+	}
+
+	if e2, ok := clone.cause.(*Error); ok {
+		clone.cause = e2.ReplaceField(key, newVal)
+	}
+	return clone
+}
+
+// CopyFieldsTo copies the custom fields of this error (i.e. all fields except op, kind and cause) to dst and returns
+// dst. Fields that already exist in dst are overwritten, others are appended in their original order. Only the fields
+// of this error are copied, not those of nested causes:
+//
+//	res := errors.E("handle request", errors.K.Invalid)
+//	return err.CopyFieldsTo(res)
+//
+// Returns dst unchanged if this error is nil, and nil if dst is nil.
+func (e *Error) CopyFieldsTo(dst *Error) *Error {
+	if e == nil || dst == nil {
+		return dst
+	}
+	for i := 0; i+1 < len(e.fields); i += 2 {
+		dst.fields.Set(e.fields[i].(string), e.fields[i+1])
+	}
+	return dst
+}
+
+// Clone returns a copy of this error that can be modified without affecting the original, e.g. in order to add fields
+// to a shared error. The fields are copied, whereas the cause is shared with the original. The clone remembers the
+// original, so that errors.Is(clone, original) returns true. Returns nil if e is nil.
+func (e *Error) Clone() *Error {
+	if e == nil {
+		return nil
+	}
+	clone := e.clone()
+	clone.origin = e
+	return clone
+}
+
+// clone returns a shallow copy of this error with its own copy of the fields.
+func (e *Error) clone() *Error {
+	clone := *e
+	clone.fields = make([]interface{}, len(e.fields))
+	copy(clone.fields, e.fields)
 	return &clone
 }
 
@@ -722,6 +1899,100 @@ func (e *Error) FormatError(printStack bool, fieldOrder ...string) string {
 	return e.toString(printStack, fieldOrder...)
 }
 
+// Summary returns a concise, human-readable one-line description of the error, intended for user-facing surfaces. It
+// combines the op, the effective kind and the message of the error or its nested errors if present - see Message():
+//
+//	errors.E("download", errors.K.NotExist).Summary()                   --> "download failed: item does not exist"
+//	errors.E("download", errors.K.IO, "message", "disk full").Summary() --> "download failed: I/O error: disk full"
+//	errors.E(errors.K.Permission).Summary()                             --> "permission denied"
+//
+// In contrast to Error(), no other fields, nested causes or the stacktrace are included.
+func (e *Error) Summary() string {
+	return e.SummaryIn("")
+}
+
+// SummaryIn is like Summary, but uses the description of the kind in the given language. See Kind.Describe().
+func (e *Error) SummaryIn(lang string) string {
+	if e == nil {
+		return ""
+	}
+	sb := strings.Builder{}
+	if e.op != "" {
+		sb.WriteString(e.op)
+		sb.WriteString(" failed: ")
+	}
+	sb.WriteString(e.Kind().Describe(lang))
+	if msg := Message(e); msg != "" {
+		sb.WriteString(": ")
+		sb.WriteString(msg)
+	}
+	return sb.String()
+}
+
+// Summary returns the result of calling the Summary() method on the given err if it is an *Error. Returns err.Error()
+// for other errors and "" if err is nil.
+func Summary(err error) string {
+	return SummaryIn(err, "")
+}
+
+// SummaryIn returns the result of calling the SummaryIn() method on the given err if it is an *Error. Returns
+// err.Error() for other errors and "" if err is nil.
+func SummaryIn(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	if e, ok := err.(*Error); ok {
+		return e.SummaryIn(lang)
+	}
+	return err.Error()
+}
+
+// GoString returns a Go-syntax representation of the error that can be copy-pasted back into code, e.g.
+//
+//	errors.E("op", errors.K.Invalid, errors.E("read", errors.Str("EOF")), "k1", "v1")
+//
+// It is used when printing the error with the %#v verb. Non-*Error causes are represented with errors.Str() of their
+// error message and field values with their %#v representation. The stacktrace is omitted.
+func (e *Error) GoString() string {
+	if e == nil {
+		return "(*errors.Error)(nil)"
+	}
+
+	args := make([]string, 0, 3+len(e.fields))
+	if e.op != "" || len(e.fields) > 0 {
+		// an op (even empty) is needed if there are fields, otherwise the first key would be used as op
+		args = append(args, strconv.Quote(e.op))
+	}
+	if e.kind != "" {
+		args = append(args, kindGoString(e.kind))
+	} else if e.defaultKind != "" {
+		args = append(args, kindGoString(e.defaultKind)+".Default()")
+	}
+	switch cause := e.cause.(type) {
+	case nil:
+	case *Error:
+		args = append(args, cause.GoString())
+	default:
+		args = append(args, "errors.Str("+strconv.Quote(cause.Error())+")")
+	}
+	for i := 0; i+1 < len(e.fields); i += 2 {
+		args = append(args, strconv.Quote(e.fields[i].(string)), fmt.Sprintf("%#v", e.fields[i+1]))
+	}
+	return "errors.E(" + strings.Join(args, ", ") + ")"
+}
+
+// kindGoString returns the Go-syntax representation of the given kind: the name of the pre-defined kind in K, or a
+// type conversion for custom kinds.
+func kindGoString(kind Kind) string {
+	kinds := reflect.ValueOf(K)
+	for i := 0; i < kinds.NumField(); i++ {
+		if kinds.Field(i).Interface() == kind {
+			return "errors.K." + kinds.Type().Field(i).Name
+		}
+	}
+	return "errors.Kind(" + strconv.Quote(string(kind)) + ")"
+}
+
 // Str is an alias for the standard errors.New() function
 func Str(text string) error {
 	return stderrors.New(text)
@@ -741,6 +2012,22 @@ func Str(text string) error {
 //
 // tests whether err is an Error with op=authorize and kind=Permission.
 func Match(err1, err2 error) bool {
+	return match(err1, err2, nil)
+}
+
+// MatchExcept is like Match, but ignores the fields with the given keys at every level of the error chain. This is
+// useful for matching errors that contain volatile fields like timestamps or durations.
+//
+// For example:
+//
+//	MatchExcept(errors.E("download", errors.K.IO, "file", f), err, "duration")
+//
+// tests whether err is an Error with op=download, kind=IO and file=f, regardless of the value of its "duration" field.
+func MatchExcept(err1, err2 error, ignoreKeys ...string) bool {
+	return match(err1, err2, ignoreKeys)
+}
+
+func match(err1, err2 error, ignoreKeys []string) bool {
 	if err1 == nil {
 		return err2 == nil
 	}
@@ -755,7 +2042,7 @@ func Match(err1, err2 error) bool {
 		if !ok2 {
 			return reflect.DeepEqual(err1, err2)
 		}
-		return Match(err1, e2.cause)
+		return match(err1, e2.cause, ignoreKeys)
 	}
 	if !ok2 {
 		return false
@@ -764,12 +2051,24 @@ func Match(err1, err2 error) bool {
 	if e1.op != "" && e1.op != e2.op {
 		return false
 	}
-	if e1.kind != "" && e1.kind != e2.Kind() {
+	if e1.kind != "" && !KindEqual(e1.kind, e2.Kind()) {
+		return false
+	}
+
+	ignored := func(key string) bool {
+		for _, k := range ignoreKeys {
+			if k == key {
+				return true
+			}
+		}
 		return false
 	}
 
 	for i := 0; i+1 < len(e1.fields); i += 2 {
 		key := e1.fields[i].(string)
+		if ignored(key) {
+			continue
+		}
 		val1 := e1.fields[i+1]
 
 		val2, ok := e2.fields.Get(key)
@@ -780,7 +2079,7 @@ func Match(err1, err2 error) bool {
 		var cause1, cause2 error
 		if cause1, ok = val1.(error); ok {
 			if cause2, ok = val2.(error); ok {
-				return Match(cause1, cause2)
+				return match(cause1, cause2, ignoreKeys)
 			}
 			return false
 		}
@@ -790,11 +2089,108 @@ func Match(err1, err2 error) bool {
 	}
 
 	if e1.cause != nil {
-		return Match(e1.cause, e2.cause)
+		return match(e1.cause, e2.cause, ignoreKeys)
 	}
 	return true
 }
 
+// Equal reports whether this error and the other error are structurally equal: op, kind, default kind and fields (same
+// keys in the same order with deeply equal values) must be identical, and the causes must be equal according to the
+// package-level Equal function. Stacktraces are ignored.
+//
+// In contrast to Match, Equal does not perform subset matching: a field that is present in only one of the errors makes
+// them unequal.
+func (e *Error) Equal(other *Error) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	if e.op != other.op || e.kind != other.kind || e.defaultKind != other.defaultKind {
+		return false
+	}
+	if len(e.fields) != len(other.fields) {
+		return false
+	}
+	for i := 0; i+1 < len(e.fields); i += 2 {
+		if e.fields[i] != other.fields[i] {
+			return false
+		}
+		val1, val2 := e.fields[i+1], other.fields[i+1]
+		if err1, ok := val1.(error); ok {
+			err2, ok := val2.(error)
+			if !ok || !Equal(err1, err2) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(val1, val2) {
+			return false
+		}
+	}
+	return Equal(e.cause, other.cause)
+}
+
+// Equal compares two errors for strict structural equality. If both errors are of type *Error, it returns the result
+// of err1.Equal(err2). Otherwise it returns reflect.DeepEqual(err1, err2).
+func Equal(err1, err2 error) bool {
+	if err1 == nil || err2 == nil {
+		return err1 == nil && err2 == nil
+	}
+
+	e1, ok1 := err1.(*Error)
+	e2, ok2 := err2.(*Error)
+	if ok1 && ok2 {
+		return e1.Equal(e2)
+	}
+	if ok1 || ok2 {
+		return false
+	}
+	return reflect.DeepEqual(err1, err2)
+}
+
+// Comparer returns a function that compares two errors with Error.Equal(), hence ignoring stacktraces. It is suitable
+// as comparer for github.com/google/go-cmp:
+//
+//	opt := cmp.Comparer(errors.Comparer())
+//	if diff := cmp.Diff(want, got, opt); diff != "" {
+//		t.Errorf("mismatch (-want +got):\n%s", diff)
+//	}
+//
+// Note that cmp uses the Equal method of *Error automatically when comparing two *Error values directly. The comparer
+// is needed for values that cmp would otherwise traverse, e.g. structs holding *Error fields with AllowUnexported
+// options.
+func Comparer() func(a, b *Error) bool {
+	return func(a, b *Error) bool {
+		return a.Equal(b)
+	}
+}
+
+// CauseOfKind returns the outermost *Error in the chain formed by this error and its nested causes whose explicit kind
+// equals k. Returns nil if there is no such error. In contrast to IsKind, which reports whether the effective kind of
+// any error in the chain matches, CauseOfKind only considers explicitly set kinds and returns the matching error:
+//
+//	if timeout := err.CauseOfKind(errors.K.Timeout); timeout != nil {
+//		log.Info("timed out", "after", timeout.Field("duration"))
+//	}
+func (e *Error) CauseOfKind(k Kind) *Error {
+	for ex := e; ex != nil; {
+		if ex.kind == k {
+			return ex
+		}
+		ex, _ = ex.cause.(*Error)
+	}
+	return nil
+}
+
+// CauseOfKind returns the result of calling the CauseOfKind() method on the given err if it is an *Error. Returns nil
+// otherwise.
+func CauseOfKind(err error, k Kind) *Error {
+	e, ok := err.(*Error)
+	if !ok {
+		return nil
+	}
+	return e.CauseOfKind(k)
+}
+
 // IsNotExist reports whether err is an *Error of Kind NotExist. Returns false if err is nil.
 func IsNotExist(err error) bool {
 	return IsKind(K.NotExist, err)
@@ -808,13 +2204,125 @@ func IsKind(expected Kind, err interface{}) bool {
 		if !ok || e == nil {
 			return false
 		}
-		if e.Kind() == expected {
+		if KindEqual(e.Kind(), expected) {
 			return true
 		}
 		err = e.cause
 	}
 }
 
+// IsOp reports whether err is an *Error with the given op or has such an *Error in its cause chain.
+// Returns false if err is nil or op is empty.
+func IsOp(op string, err error) bool {
+	if op == "" {
+		return false
+	}
+	var e interface{} = err
+	for {
+		ex, ok := e.(*Error)
+		if !ok || ex == nil {
+			return false
+		}
+		if ex.op == op {
+			return true
+		}
+		e = ex.cause
+	}
+}
+
+// Code returns the error code of the given error, searching the cause chain until a code is found - see
+// Error.WithCode(). Returns "" if err is not an *Error or has no code.
+func Code(err error) string {
+	code, _ := GetField(err, "code")
+	return code
+}
+
+// IsCode reports whether err is an *Error that has the given code in its cause chain. Returns false if err is nil.
+func IsCode(code string, err error) bool {
+	var e interface{} = err
+	for {
+		ex, ok := e.(*Error)
+		if !ok || ex == nil {
+			return false
+		}
+		if c, ok := ex.fields.Get("code"); ok && toString(c) == code {
+			return true
+		}
+		e = ex.cause
+	}
+}
+
+// Message returns the message of the given error, searching the cause chain until a message is found - see
+// Error.WithMessage(). Returns "" if err is not an *Error or has no message.
+func Message(err error) string {
+	msg, _ := GetField(err, "message")
+	return msg
+}
+
+// RetryAfter returns the retry duration set with WithRetryAfter() on the given error or any of its nested errors.
+// Returns 0 and false if err is not an *Error, if the duration is not set or if it cannot be parsed.
+func RetryAfter(err error) (time.Duration, bool) {
+	val, ok := GetField(err, "retry_after")
+	if !ok {
+		return 0, false
+	}
+	d, perr := time.ParseDuration(val)
+	if perr != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// TransitTime returns the time between the innermost and the outermost timestamp set with WithTimestamp() in the cause
+// chain of the given error, e.g. the time an error took from its origin on a remote host until it was wrapped locally:
+//
+//	remote: errors.E("read", errors.K.IO, io.EOF).WithTimestamp(time.Now())
+//	local:  errors.E("fetch", unmarshalledErr).WithTimestamp(time.Now())
+//	errors.TransitTime(err) --> local timestamp - remote timestamp
+//
+// Timestamps that were unmarshaled from JSON as RFC3339Nano strings are parsed. Returns 0 and false if err is not an
+// *Error or if there are less than two timestamps in the chain. The result is subject to clock differences between
+// hosts and may be negative.
+func TransitTime(err error) (time.Duration, bool) {
+	var outer, inner time.Time
+	count := 0
+	var e interface{} = err
+	for {
+		ex, ok := e.(*Error)
+		if !ok || ex == nil {
+			break
+		}
+		if ts, ok := ex.timestamp(); ok {
+			if count == 0 {
+				outer = ts
+			}
+			inner = ts
+			count++
+		}
+		e = ex.cause
+	}
+	if count < 2 {
+		return 0, false
+	}
+	return outer.Sub(inner), true
+}
+
+// timestamp returns the time stored in the "timestamp" field of this error, parsing it if it is a string.
+func (e *Error) timestamp() (time.Time, bool) {
+	val, ok := e.fields.Get("timestamp")
+	if !ok {
+		return time.Time{}, false
+	}
+	switch v := val.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		ts, err := time.Parse(time.RFC3339Nano, v)
+		return ts, err == nil
+	}
+	return time.Time{}, false
+}
+
 // GetRoot returns the innermost nested *Error of the given error, or nil if the provided object is not an *Error.
 func GetRoot(err interface{}) *Error {
 	var root *Error
@@ -835,7 +2343,7 @@ func GetRootCause(err error) error {
 		if !ok {
 			return err
 		}
-		if e.cause == nil {
+		if e == nil || e.cause == nil {
 			return NilError
 		}
 		err = e.cause
@@ -843,6 +2351,27 @@ func GetRootCause(err error) error {
 	return NilError
 }
 
+// SameRootCause returns true if the two errors have the same root cause as returned by GetRootCause(), regardless of
+// the errors wrapping it. Root causes are compared with Match() if they are *Error instances and by their error message
+// otherwise:
+//
+//	errors.SameRootCause(errors.E("read", io.EOF), errors.E("fetch", errors.E("copy", io.EOF))) --> true
+//
+// Two errors without root cause (see NilError) are considered the same. Errors with and without root cause are not.
+func SameRootCause(err1, err2 error) bool {
+	root1 := GetRootCause(err1)
+	root2 := GetRootCause(err2)
+	if root1 == NilError || root2 == NilError {
+		return root1 == root2
+	}
+	e1, ok1 := root1.(*Error)
+	e2, ok2 := root2.(*Error)
+	if ok1 && ok2 {
+		return Match(e1, e2)
+	}
+	return root1.Error() == root2.Error()
+}
+
 // UnmarshalJsonErrorList unmarshals a list of errors. JSON objects are unmarshalled into Error objects, strings into
 // generic errors created with Str(s). Empty objects or strings are ignored.
 //
@@ -870,6 +2399,9 @@ func ClearStacktrace(err error) error {
 	if !ok {
 		return err
 	}
+	if e == nil {
+		return nil
+	}
 	return e.ClearStacktrace()
 }
 
@@ -889,8 +2421,10 @@ func Ignore(f func() error) {
 	_ = f()
 }
 
-// Wrap wraps the given error in an Error instance with E(err) if err is not an *Error itself - otherwise returns err as
-// *Error unchanged. Returns nil if err is nil.
+// Wrap wraps the given error in an Error instance with E(err) if err is not an *Error itself. Additional args are added
+// with With(). If err is an *Error, it is returned unchanged if there are no additional args. Otherwise the args are
+// added to a clone of err - see Clone() - since the given *Error might be shared, e.g. a package-level sentinel error.
+// Returns nil if err is nil.
 func Wrap(err error, args ...interface{}) *Error {
 	if err == nil {
 		return nil
@@ -898,6 +2432,8 @@ func Wrap(err error, args ...interface{}) *Error {
 	e, ok := err.(*Error)
 	if !ok {
 		e = E(err)
+	} else if len(args) > 0 {
+		e = e.Clone()
 	}
 	if len(args) > 0 {
 		_ = e.With(args...)
@@ -905,27 +2441,197 @@ func Wrap(err error, args ...interface{}) *Error {
 	return e
 }
 
+// Coerce converts the given error into an *Error: it returns err itself if it is an *Error, and wraps it with E(err)
+// otherwise. In particular, an *ErrorList is wrapped into an *Error that has the list as cause. Returns nil if err is
+// nil, including a nil *Error or *ErrorList.
+func Coerce(err error) *Error {
+	if isNil(err) {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return E(err).dropStackFrames(1)
+}
+
+// EnsureKind ensures that the given error has a kind other than K.Other, e.g. at the public boundary of a library:
+//
+//	func (c *Client) Fetch(url string) (err error) {
+//		defer func() { err = errors.EnsureKind(err, errors.K.IO) }()
+//		...
+//	}
+//
+// If err is an *Error with effective kind K.Other, a clone of err with the fallback kind is returned. Errors that are
+// not an *Error are wrapped in a new error with the fallback kind. Other errors are returned unchanged. Returns nil if
+// err is nil.
+func EnsureKind(err error, fallback Kind) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		return E(fallback, err).dropStackFrames(1)
+	}
+	if e == nil {
+		return nil
+	}
+	if e.Kind() != K.Other {
+		return e
+	}
+	return e.Clone().WithKind(fallback)
+}
+
+// Wrapf wraps the given error in a new error with the given op and additional args as passed to E(). The new error
+// inherits the kind of err unless a kind is specified in args. It is a shortcut for the common wrapping idiom
+//
+//	errors.E("read config", err, "file", f)
+//
+// which can be written as
+//
+//	errors.Wrapf(err, "read config", "file", f)
+//
+// In contrast to E(), Wrapf returns nil if err is nil.
+func Wrapf(err error, op string, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	return E(append([]interface{}{op, err}, args...)...).dropStackFrames(1)
+}
+
+// WrapBounded creates a new error with E(args...) and sets the given err as its cause, like E(args..., err). The
+// returned error contains at most maxDepth *Error instances in its cause chain, including itself: if the cause chain
+// of err contains more than maxDepth-1 *Error instances, the chain is bounded first. The outermost maxDepth-2 errors
+// are retained, and all older errors are collapsed into a single leaf, which is a copy of the innermost *Error of the
+// chain - hence preserving the kind and message of the root cause. The leaf records the total number of dropped errors
+// in the "collapsed" field. The given err itself is not modified.
+//
+// WrapBounded is useful in retry loops that wrap the previous attempt's error, where the cause chain would otherwise
+// grow without bound:
+//
+//	err = errors.WrapBounded(err, 10, "download", "attempt", attempt)
+//
+// A maxDepth smaller than 2 is treated as 2, since the new error and the leaf are always retained. Returns nil if err
+// is nil.
+func WrapBounded(err error, maxDepth int, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	return created(newErrorWithStack(args).WithCause(bound(err, maxDepth-1)))
+}
+
+// bound limits the number of *Error instances in the cause chain of err to maxDepth as described in WrapBounded.
+func bound(err error, maxDepth int) error {
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+
+	depth := Depth(err)
+	if depth <= maxDepth {
+		return err
+	}
+
+	leaf := GetRoot(err).clone()
+	collapsed := depth - maxDepth
+	if prev, ok := leaf.fields.Get("collapsed"); ok {
+		// the chain has been bounded before
+		if n, ok := toInt(prev); ok {
+			collapsed += n
+		}
+	}
+	leaf.fields.Set("collapsed", collapsed)
+	if maxDepth == 1 {
+		return leaf
+	}
+
+	res := err.(*Error).clone()
+	last := res
+	for i := 1; i < maxDepth-1; i++ {
+		next := last.cause.(*Error).clone()
+		last.cause = next
+		last = next
+	}
+	last.cause = leaf
+	return res
+}
+
+// Capture creates a new error with E(args...) and sets the given err as its cause. In addition, it records
+// classification information of err as fields, so that it survives even if the concrete type of err is lost, e.g.
+// after serialization:
+//   - "temporary": the result of Temporary() if err or an error in its chain implements Temporary() bool
+//   - "timeout": the result of Timeout() if err or an error in its chain implements Timeout() bool
+//   - "canceled": true if errors.Is(err, context.Canceled)
+//
+// Returns nil if err is nil.
+func Capture(err error, args ...interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	e := newErrorWithStack(args).WithCause(err)
+
+	var temporary interface{ Temporary() bool }
+	if stderrors.As(err, &temporary) {
+		e.fields.Set("temporary", temporary.Temporary())
+	}
+	var timeout interface{ Timeout() bool }
+	if stderrors.As(err, &timeout) {
+		e.fields.Set("timeout", timeout.Timeout())
+	}
+	if stderrors.Is(err, context.Canceled) {
+		e.fields.Set("canceled", true)
+	}
+	return created(e)
+}
+
 // FromContext creates an error from the given context and additional error arguments as passed to E(). It returns
 //   - nil if ctx.Err() returns nil
 //   - an error from the given args and kind Timeout if the ctx timed out
 //   - an error from the given args and kind Cancelled if the ctx was cancelled
 //   - an error from the given args and the cause set to ctx.Err() otherwise.
+//
+// If TraceExtractor is set, the trace and span IDs extracted from ctx are added to the returned error.
 func FromContext(ctx context.Context, args ...interface{}) *Error {
 	if ctx == nil {
 		return nil
 	}
-	switch ctx.Err() {
-	case nil:
+	err := ctx.Err()
+	if err == nil {
 		return nil
+	}
+	e := newErrorWithStack(args)
+	switch err {
 	case context.DeadlineExceeded:
-		return E(args...).WithKind(K.Timeout)
+		_ = e.WithKind(K.Timeout)
 	case context.Canceled:
-		return E(args...).WithKind(K.Cancelled)
+		_ = e.WithKind(K.Cancelled)
+	default:
+		_ = e.WithCause(err)
 	}
-	return E(args...).WithCause(ctx.Err())
+	return created(withTraceFrom(ctx, e))
 }
 
 // TypeOf returns the type of the given value as string.
 func TypeOf(val interface{}) string {
 	return fmt.Sprintf("%T", val)
 }
+
+// Unexpected creates an error of kind K.Internal with op "unexpected" for cases that should never happen, e.g. in the
+// default branch of a switch statement that is expected to be exhaustive:
+//
+//	switch state {
+//	case Running: ...
+//	case Stopped: ...
+//	default:
+//		return errors.Unexpected("state", state, "job", id)
+//	}
+//	--> op [unexpected] kind [internal error] what [state] value [main.State(3)] job [42]
+//
+// The value is stored in the "value" field as its type and its fmt.Sprint() representation. Additional args are
+// passed to E().
+func Unexpected(what string, value interface{}, args ...interface{}) *Error {
+	return E(append([]interface{}{
+		"unexpected",
+		K.Internal,
+		"what", what,
+		"value", TypeOf(value) + "(" + fmt.Sprint(value) + ")",
+	}, args...)...).dropStackFrames(1)
+}
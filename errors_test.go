@@ -3,6 +3,7 @@ package errors_test
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"strings"
@@ -87,11 +88,77 @@ func TestE_invalidArgs(t *testing.T) {
 	assert.Equal(t, "kind [unclassified error] arg1 [7] arg2 [<missing>] cause [some error]", err.Error())
 }
 
+func TestError_WithSource(t *testing.T) {
+	err := errors.NoTrace("render", errors.K.Invalid).WithSource("templates/user.tmpl", 12)
+	assert.Equal(t, "op [render] kind [invalid] source [templates/user.tmpl:12]", err.Error())
+	assert.Nil(t, (*errors.Error)(nil).WithSource("a.tmpl", 1))
+}
+
+func TestMissingValuePlaceholder(t *testing.T) {
+	defer func(p string) { errors.MissingValuePlaceholder = p }(errors.MissingValuePlaceholder)
+	errors.MissingValuePlaceholder = "∅"
+
+	err := errors.NoTrace("op", "arg1", 7, "arg2")
+	assert.Equal(t, "∅", err.Field("arg2"))
+	assert.Equal(t, "op [op] kind [unclassified error] arg1 [7] arg2 [∅]", err.Error())
+
+	err = errors.NoTrace("op").With("arg3")
+	assert.Equal(t, "op [op] kind [unclassified error] arg3 [∅]", err.Error())
+}
+
+func TestStrictArgs(t *testing.T) {
+	defer func() { errors.StrictArgs = false }()
+
+	err := errors.NoTrace(42, "file", "a.txt")
+	assert.Equal(t, "kind [unclassified error] 42 [file] a.txt [<missing>]", err.Error())
+
+	errors.StrictArgs = true
+
+	err = errors.NoTrace(42, "file", "a.txt")
+	assert.Equal(t, "kind [unclassified error] 42 [file] a.txt [<missing>] invalid_leading_arg [int]", err.Error())
+
+	for _, err = range []*errors.Error{
+		errors.NoTrace("read", "file", "a.txt"),
+		errors.NoTrace(errors.K.IO, "file", "a.txt"),
+		errors.NoTrace(errors.DefaultKind(errors.K.IO), "file", "a.txt"),
+		errors.NoTrace(io.EOF, "file", "a.txt"),
+		errors.NoTrace(nil, "file", "a.txt"),
+	} {
+		assert.Nil(t, err.Field("invalid_leading_arg"), err.Error())
+	}
+}
+
 func TestE_nilArgs(t *testing.T) {
 	err := errors.E(nil, nil, nil, nil)
 	assert.Equal(t, "kind [unclassified error]", err.Error())
 }
 
+func TestWithIf(t *testing.T) {
+	err := errors.NoTrace("op").
+		WithIf(true, "k1", "v1").
+		WithIf(false, "k2", "v2").
+		WithUnless(true, "k3", "v3").
+		WithUnless(false, "k4", "v4", errors.K.IO)
+	assert.Equal(t, "op [op] kind [I/O error] k1 [v1] k4 [v4]", err.Error())
+
+	assert.Nil(t, (*errors.Error)(nil).WithIf(true, "k", "v"))
+	assert.Nil(t, (*errors.Error)(nil).WithUnless(false, "k", "v"))
+}
+
+func TestError_Tap(t *testing.T) {
+	var tapped []string
+	tap := func(e *errors.Error) {
+		tapped = append(tapped, e.Error())
+	}
+
+	err := errors.NoTrace("op", errors.K.IO).Tap(tap).With("k", "v").Tap(tap).Tap(nil)
+	assert.Equal(t, "op [op] kind [I/O error] k [v]", err.Error())
+	assert.Equal(t, []string{"op [op] kind [I/O error]", "op [op] kind [I/O error] k [v]"}, tapped)
+
+	assert.Nil(t, (*errors.Error)(nil).Tap(tap))
+	assert.Len(t, tapped, 2)
+}
+
 func TestWith(t *testing.T) {
 	fields := []interface{}{"key1", "val1", "key2", "val2", "key3", "val3"}
 	for i := 0; i < 2; i++ {
@@ -156,6 +223,27 @@ func TestIsKind(t *testing.T) {
 	assert.False(t, errors.IsKind(errors.K.NotExist, errors.E("op", errors.K.Invalid, errors.E("op_nested", errors.K.Other))))
 }
 
+func TestKindOr(t *testing.T) {
+	assert.Equal(t, errors.K.IO, errors.KindOr(errors.NoTrace("op", errors.K.IO), errors.K.Internal))
+	assert.Equal(t, errors.K.IO, errors.KindOr(errors.NoTrace("op", errors.NoTrace(errors.K.IO)), errors.K.Internal))
+	assert.Equal(t, errors.K.Internal, errors.KindOr(errors.NoTrace("op"), errors.K.Internal))
+	assert.Equal(t, errors.K.Internal, errors.KindOr(errors.NoTrace("op", errors.K.Other), errors.K.Internal))
+	assert.Equal(t, errors.K.Internal, errors.KindOr(io.EOF, errors.K.Internal))
+	assert.Equal(t, errors.K.Internal, errors.KindOr(nil, errors.K.Internal))
+	assert.Equal(t, errors.K.Internal, (*errors.Error)(nil).KindOr(errors.K.Internal))
+}
+
+func TestIsOp(t *testing.T) {
+	err := errors.NoTrace("download", errors.NoTrace("open", io.EOF))
+	assert.True(t, errors.IsOp("download", err))
+	assert.True(t, errors.IsOp("open", err))
+	assert.False(t, errors.IsOp("read", err))
+	assert.False(t, errors.IsOp("", errors.NoTrace(errors.K.IO)))
+	assert.False(t, errors.IsOp("open", io.EOF))
+	assert.False(t, errors.IsOp("open", nil))
+	assert.False(t, errors.IsOp("open", (*errors.Error)(nil)))
+}
+
 func TestIsNotExist(t *testing.T) {
 	assert.False(t, errors.IsNotExist(errors.E("op", errors.K.IO, io.EOF)))
 	assert.True(t, errors.IsNotExist(errors.E("op", errors.E("op_nested", errors.K.NotExist))))
@@ -336,6 +424,71 @@ func TestMatch(t *testing.T) {
 	}
 }
 
+func TestMatchExcept(t *testing.T) {
+	got := errors.E("send email", errors.K.IO, "duration", "1s",
+		errors.E("connect", errors.K.IO, errors.Str("network unreachable"), "host", "mail", "duration", "700ms"))
+
+	tests := []struct {
+		expect     error
+		ignoreKeys []string
+		matched    bool
+	}{
+		{errors.E("send email", "duration", "2s"), nil, false},
+		{errors.E("send email", "duration", "2s"), []string{"duration"}, true},
+		{errors.E("send email", "duration", "2s"), []string{"other"}, false},
+		{errors.E("send email", errors.E("connect", "duration", "1s")), nil, false},
+		{errors.E("send email", errors.E("connect", "duration", "1s")), []string{"duration"}, true},
+		{errors.E("send email", errors.E("connect", "host", "smtp", "duration", "1s")), []string{"duration"}, false},
+		{errors.E("send email", errors.E("connect", "host", "smtp", "duration", "1s")), []string{"duration", "host"}, true},
+		{errors.E("receive email", "duration", "1s"), []string{"duration"}, false},
+	}
+
+	for idx, test := range tests {
+		matched := errors.MatchExcept(test.expect, got, test.ignoreKeys...)
+		assert.Equal(t, test.matched, matched, "#%d expect [%q] ignore %v", idx, test.expect, test.ignoreKeys)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	var nilErr *errors.Error
+	errConnect := errors.E("connect", errors.K.IO, errors.Str("network unreachable"), "k1", "v1", "k2", "v2")
+
+	tests := []struct {
+		err1, err2 error
+		equal      bool
+	}{
+		{nil, nil, true},
+		{io.EOF, io.EOF, true},
+		{io.EOF, nil, false},
+		{nil, io.EOF, false},
+		{io.EOF, errors.E(io.EOF), false},
+		{errors.E(io.EOF), io.EOF, false},
+		{errors.E(), errors.NoTrace(), true},
+		{errors.E(op, errors.K.Invalid, io.EOF, "k1", "v1"), errors.NoTrace(op, errors.K.Invalid, io.EOF, "k1", "v1"), true},
+		{errConnect, errors.E("connect", errors.K.IO, errors.Str("network unreachable"), "k1", "v1", "k2", "v2"), true},
+		{errors.E("send", errConnect), errors.E("send", errors.E(errConnect)), false},
+		{errors.E(op1), errors.E(op2), false},
+		{errors.E(errors.K.IO), errors.E(errors.K.Invalid), false},
+		{errors.E(errors.K.IO), errors.E(errors.K.IO.Default()), false},
+		{errors.E(io.EOF), errors.E(io.ErrUnexpectedEOF), false},
+		{errors.E(op, "k1", "v1"), errors.E(op, "k1", 1), false},
+		{errors.E(op, "k1", "v1", "k2", "v2"), errors.E(op, "k2", "v2", "k1", "v1"), false},
+		{errors.E(op, "k1", "v1"), errors.E(op, "k1", "v1", "k2", "v2"), false},
+		{errors.E(op, "k1", "v1", "k2", "v2"), errors.E(op, "k1", "v1"), false},
+		{errors.E(op, "nested", errConnect), errors.E(op, "nested", errors.E("connect", errors.K.IO, errors.Str("network unreachable"), "k1", "v1", "k2", "v2")), true},
+		{errors.E(op, "nested", errConnect), errors.E(op, "nested", "not an error"), false},
+		{nilErr, nilErr, true},
+	}
+
+	for idx, test := range tests {
+		assert.Equal(t, test.equal, errors.Equal(test.err1, test.err2), "#%d err1 [%q] err2 [%q]", idx, test.err1, test.err2)
+	}
+
+	assert.True(t, nilErr.Equal(nil))
+	assert.False(t, nilErr.Equal(errors.E()))
+	assert.False(t, errors.E().Equal(nil))
+}
+
 func TestSeparator(t *testing.T) {
 	defer func(prev string) {
 		errors.Separator = prev
@@ -348,6 +501,125 @@ func TestSeparator(t *testing.T) {
 	assert.Equal(t, want, err.Error())
 }
 
+func TestNestedIndent(t *testing.T) {
+	defer func(prev string) {
+		errors.NestedIndent = prev
+	}(errors.NestedIndent)
+
+	err := errors.NoTrace("fetch", errors.NoTrace("read", errors.NoTrace("open", errors.K.IO, io.EOF)))
+
+	// default: single tab, not progressive
+	assert.Equal(t, "op [fetch] kind [I/O error] cause:\n\top [read] kind [I/O error] cause:\n\top [open] kind [I/O error] cause [EOF]", err.Error())
+
+	errors.NestedIndent = "  "
+	assert.Equal(t, "op [fetch] kind [I/O error] cause:\n  op [read] kind [I/O error] cause:\n    op [open] kind [I/O error] cause [EOF]", err.Error())
+}
+
+func TestInlineCauseList(t *testing.T) {
+	defer func() {
+		errors.InlineCauseList = false
+		errors.NestedIndent = ""
+	}()
+
+	list := errors.Append(errors.NoTrace("read", errors.K.IO, io.EOF), io.ErrUnexpectedEOF)
+	err := errors.NoTrace("batch", errors.K.IO, list)
+	assert.Equal(t, "op [batch] kind [I/O error] cause [error-list count [2]\n"+
+		"\t0: op [read] kind [I/O error] cause [EOF]\n"+
+		"\t1: unexpected EOF\n]", err.Error())
+
+	errors.InlineCauseList = true
+	assert.Equal(t, "op [batch] kind [I/O error] cause:\n"+
+		"\terror-list count [2]\n"+
+		"\t\t0: op [read] kind [I/O error] cause [EOF]\n"+
+		"\t\t1: unexpected EOF", err.Error())
+
+	errors.NestedIndent = "  "
+	assert.Equal(t, "op [batch] kind [I/O error] cause:\n"+
+		"  error-list count [2]\n"+
+		"    0: op [read] kind [I/O error] cause [EOF]\n"+
+		"    1: unexpected EOF", err.Error())
+
+	// single error lists are not affected
+	err = errors.NoTrace("batch", errors.K.IO, &errors.ErrorList{Errors: []error{io.EOF}})
+	assert.Equal(t, "op [batch] kind [I/O error] cause [EOF]", err.Error())
+}
+
+func TestElideRepeatedKind(t *testing.T) {
+	defer func(prev bool) {
+		errors.ElideRepeatedKind = prev
+	}(errors.ElideRepeatedKind)
+
+	err := errors.NoTrace("fetch", errors.NoTrace("read", errors.NoTrace("open", errors.K.IO, io.EOF)))
+	mixed := errors.NoTrace("fetch", errors.K.Unavailable, errors.NoTrace("read", errors.NoTrace("open", errors.K.IO, io.EOF)))
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	errors.ElideRepeatedKind = true
+	require.Equal(t, "op [fetch] kind [I/O error] cause:\n\top [read] cause:\n\top [open] cause [EOF]", err.Error())
+	require.Equal(t, "op [fetch] kind [service unavailable] cause:\n\top [read] kind [I/O error] cause:\n\top [open] cause [EOF]", mixed.Error())
+
+	// JSON is not affected
+	jsn2, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, string(jsn), string(jsn2))
+}
+
+func TestColorOutput(t *testing.T) {
+	defer func(prev bool) {
+		errors.ColorOutput = prev
+	}(errors.ColorOutput)
+
+	err := errors.NoTrace("check", errors.K.Warn, errors.NoTrace("read", errors.K.IO))
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	require.Equal(t, "op [check] kind [warning] cause:\n\top [read] kind [I/O error]", err.Error())
+
+	errors.ColorOutput = true
+	require.Equal(t, "op [check] kind [\x1b[33mwarning\x1b[0m] cause:\n\top [read] kind [\x1b[31mI/O error\x1b[0m]", err.Error())
+
+	// JSON is not affected
+	jsn2, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, string(jsn), string(jsn2))
+}
+
+func TestOpKindCauseKeys(t *testing.T) {
+	defer resetDefaultFieldOrder()()
+	defer func(opKey, kindKey, causeKey string) {
+		errors.OpKey, errors.KindKey, errors.CauseKey = opKey, kindKey, causeKey
+	}(errors.OpKey, errors.KindKey, errors.CauseKey)
+
+	errors.OpKey = "operation"
+	errors.KindKey = "type"
+	errors.CauseKey = "reason"
+
+	err := errors.E("send email", errors.K.IO, "k1", "v1", errors.E("connect", io.EOF))
+	require.Equal(t, "operation [send email] type [I/O error] k1 [v1] reason:\n\toperation [connect] type [unclassified error] reason [EOF]", err.Error())
+
+	require.Equal(t, "send email", err.Field("operation"))
+	require.Equal(t, errors.K.IO, err.Field("type"))
+	require.Nil(t, err.Field("op"))
+	val, ok := err.GetField("type")
+	require.True(t, ok)
+	require.Equal(t, string(errors.K.IO), val)
+
+	jsn, jerr := json.Marshal(err.ClearStacktrace())
+	require.NoError(t, jerr)
+	require.Equal(t, `{"operation":"send email","type":"I/O error","k1":"v1","reason":{"operation":"connect","type":"unclassified error","reason":"EOF"}}`, string(jsn))
+
+	var unmarshalled errors.Error
+	require.NoError(t, json.Unmarshal(jsn, &unmarshalled))
+	require.Equal(t, "send email", unmarshalled.Op())
+	require.Equal(t, errors.K.IO, unmarshalled.Kind())
+	require.Equal(t, err.Error(), unmarshalled.Error())
+
+	require.Equal(t, "send email", errors.E().With("operation", "send email").Op())
+
+	errors.DefaultFieldOrder = []string{"type", "", "reason"}
+	require.Equal(t, "type [I/O error] operation [send email] k1 [v1] reason:\n\ttype [unclassified error] operation [connect] reason [EOF]", err.Error())
+}
+
 func TestGetField(t *testing.T) {
 	e1 := errors.E("Test", "key", "val1")
 	e2 := errors.E("Test", e1, "key", "val2")
@@ -426,6 +698,461 @@ func TestField(t *testing.T) {
 	require.Nil(t, f)
 }
 
+func TestFieldDeep(t *testing.T) {
+	e1 := errors.E("Test", "key", "val1")
+	e2 := errors.E("Test", e1, "key", 2)
+
+	f := errors.FieldDeep(nil, "key")
+	require.Nil(t, f)
+
+	f = errors.FieldDeep(io.EOF, "key")
+	require.Nil(t, f)
+
+	f = errors.FieldDeep(e1, "missing_key")
+	require.Nil(t, f)
+
+	f = errors.FieldDeep(e2, "key")
+	require.Equal(t, "val1", f)
+
+	e2 = errors.E("Test", e1, "another_key", "val2")
+	f = errors.FieldDeep(e2, "key")
+	require.Equal(t, "val1", f)
+
+	e3 := errors.E("Test", e2, "key", 3)
+	f = errors.FieldDeep(e3, "key")
+	require.Equal(t, "val1", f)
+	f = errors.Field(e3, "key")
+	require.Equal(t, 3, f)
+
+	e2 = errors.E("Test", errors.E("Inner", io.EOF), "key", 2)
+	e3 = errors.E("Test", e2, "key", "val3")
+	f = errors.FieldDeep(e3, "key")
+	require.Equal(t, 2, f)
+	f = errors.FieldDeep(e3, "op")
+	require.Equal(t, "Inner", f)
+
+	fe1 := fmt.Errorf("not an elv error %s", "x")
+	e2 = errors.E("Test", fe1, "another_key", "val2")
+	e3 = errors.E("Test", e2, "yet_another_key", "val3")
+	f = errors.FieldDeep(e3, "key")
+	require.Nil(t, f)
+}
+
+func TestFieldAll(t *testing.T) {
+	require.Nil(t, errors.FieldAll(nil, "layer"))
+	require.Nil(t, errors.FieldAll(io.EOF, "layer"))
+
+	e1 := errors.E("read", io.EOF, "layer", "disk")
+	e2 := errors.E("fetch", e1, "attempt", 3)
+	e3 := errors.E("load", e2, "layer", "cache")
+	e4 := errors.E("serve", e3, "layer", "http")
+
+	require.Equal(t, []interface{}{"http", "cache", "disk"}, errors.FieldAll(e4, "layer"))
+	require.Equal(t, []interface{}{"http", "cache", "disk"}, e4.FieldAll("layer"))
+	require.Equal(t, []interface{}{3}, errors.FieldAll(e4, "attempt"))
+	require.Equal(t, []interface{}{"serve", "load", "fetch", "read"}, errors.FieldAll(e4, "op"))
+	require.Nil(t, errors.FieldAll(e4, "missing"))
+
+	e2 = errors.E("fetch", fmt.Errorf("not an elv error"), "layer", "net")
+	e3 = errors.E("load", e2, "layer", "cache")
+	require.Equal(t, []interface{}{"cache", "net"}, errors.FieldAll(e3, "layer"))
+}
+
+func TestError_Fields(t *testing.T) {
+	defer resetDefaultFieldOrder()()
+
+	err := errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt", "code", "E42")
+	require.Equal(t, "op [read] kind [I/O error] code [E42] file [a.txt] cause [EOF]", err.Error())
+	require.Equal(t, []interface{}{"op", "read", "kind", errors.K.IO, "code", "E42", "file", "a.txt", "cause", io.EOF}, err.Fields())
+
+	errors.DefaultFieldOrder = []string{"file", "", "op"}
+	require.Equal(t, "file [a.txt] kind [I/O error] code [E42] cause [EOF] op [read]", err.Error())
+	require.Equal(t, []interface{}{"file", "a.txt", "kind", errors.K.IO, "code", "E42", "cause", io.EOF, "op", "read"}, err.Fields())
+
+	require.Nil(t, (*errors.Error)(nil).Fields())
+}
+
+func TestError_NumFieldsAndKeys(t *testing.T) {
+	err := errors.NoTrace("read", errors.K.IO, errors.NoTrace("open", "path", "/tmp"), "file", "a.txt", "attempt", 2)
+	require.Equal(t, 2, err.NumFields())
+	require.Equal(t, []string{"file", "attempt"}, err.Keys())
+
+	err = errors.NoTrace("read", errors.K.IO, io.EOF)
+	require.Equal(t, 0, err.NumFields())
+	require.Nil(t, err.Keys())
+
+	require.Equal(t, 0, (*errors.Error)(nil).NumFields())
+	require.Nil(t, (*errors.Error)(nil).Keys())
+}
+
+func TestError_AllFields(t *testing.T) {
+	err := errors.NoTrace("get user", errors.K.NotExist,
+		errors.NoTrace("read", errors.K.IO, errors.NoTrace(io.EOF, "file", "a.txt"), "user", "inner"),
+		"user", "joe")
+	require.Equal(t, map[string]interface{}{
+		"op":                "get user",
+		"kind":              errors.K.NotExist,
+		"user":              "joe",
+		"cause.op":          "read",
+		"cause.kind":        errors.K.IO,
+		"cause.user":        "inner",
+		"cause.cause.kind":  errors.K.Other,
+		"cause.cause.file":  "a.txt",
+		"cause.cause.cause": "EOF",
+	}, err.AllFields())
+
+	require.Equal(t, map[string]interface{}{"kind": errors.K.Other}, errors.NoTrace().AllFields())
+	require.Nil(t, (*errors.Error)(nil).AllFields())
+}
+
+func TestComparer(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	cmp := errors.Comparer()
+	e1 := errors.E("read", errors.K.IO, io.EOF, "file", "a.txt")
+	e2 := func() *errors.Error { return errors.E("read", errors.K.IO, io.EOF, "file", "a.txt") }()
+
+	require.True(t, cmp(e1, e2))
+	require.True(t, cmp(nil, nil))
+	require.False(t, cmp(e1, nil))
+	require.False(t, cmp(nil, e2))
+	require.False(t, cmp(e1, errors.E("read", errors.K.IO, io.EOF, "file", "b.txt")))
+}
+
+func TestError_Simplify(t *testing.T) {
+	root := errors.NoTrace("read", errors.K.IO, io.EOF)
+	err := errors.NoTrace("get user", errors.NoTrace(errors.NoTrace("load", errors.NoTrace(errors.NoTrace(root)))))
+	require.Equal(t, 6, err.Depth())
+
+	simple := err.Simplify()
+	require.Equal(t, 3, simple.Depth())
+	require.Equal(t, "op [get user] kind [I/O error] cause:\n\top [load] kind [I/O error] cause:\n\top [read] kind [I/O error] cause [EOF]", simple.Error())
+	require.Equal(t, 6, err.Depth()) // original is not modified
+
+	// nodes with default kind are retained
+	err = errors.NoTrace("op", errors.NoTrace(errors.K.Invalid.Default(), root))
+	require.Equal(t, 3, err.Simplify().Depth())
+
+	// a wrapper of a non-*Error cause is replaced by the cause, an empty leaf is removed
+	require.Equal(t, 1, errors.NoTrace("op", errors.NoTrace(io.EOF)).Simplify().Depth())
+	require.Equal(t, "op [a] kind [unclassified error] cause [EOF]", errors.NoTrace("a", errors.NoTrace(errors.NoTrace(io.EOF))).Simplify().Error())
+	require.Equal(t, "op [a] kind [I/O error] cause [EOF]", errors.NoTrace("a", errors.K.IO, errors.NoTrace(io.EOF)).Simplify().Error())
+	require.Equal(t, 1, errors.NoTrace("op", errors.NoTrace()).Simplify().Depth())
+	require.Equal(t, 1, errors.NoTrace("op", errors.NoTrace(errors.NoTrace())).Simplify().Depth())
+
+	// the outermost error is always retained
+	require.Equal(t, 2, errors.NoTrace(root).Simplify().Depth())
+
+	require.Nil(t, (*errors.Error)(nil).Simplify())
+}
+
+func TestError_ReplaceField(t *testing.T) {
+	var nilErr *errors.Error
+	require.Nil(t, nilErr.ReplaceField("path", "x"))
+
+	e1 := errors.E("read", errors.K.IO, io.EOF, "path", "/home/joe/a.txt")
+	e2 := errors.E("parse", e1, "user", "joe")
+	e3 := errors.E("load", e2, "path", "/home/joe", "attempt", 2)
+
+	replaced := e3.ReplaceField("path", "<redacted>")
+	require.Equal(t, "op [load] kind [I/O error] path [<redacted>] attempt [2] cause:\n\t"+
+		"op [parse] kind [I/O error] user [joe] cause:\n\t"+
+		"op [read] kind [I/O error] path [<redacted>] cause [EOF]",
+		replaced.Error())
+
+	// the original error is unchanged
+	require.Equal(t, "op [load] kind [I/O error] path [/home/joe] attempt [2] cause:\n\t"+
+		"op [parse] kind [I/O error] user [joe] cause:\n\t"+
+		"op [read] kind [I/O error] path [/home/joe/a.txt] cause [EOF]",
+		e3.Error())
+
+	// replacing a non-existent field produces an equal copy
+	require.True(t, e3.Equal(e3.ReplaceField("missing", "x")))
+}
+
+func TestError_nil(t *testing.T) {
+	var e *errors.Error
+
+	tests := []struct {
+		name string
+		fn   func() interface{}
+		want interface{}
+	}{
+		{"Unwrap", func() interface{} { return e.Unwrap() }, nil},
+		{"MarshalJSON", func() interface{} { b, err := e.MarshalJSON(); return fmt.Sprintf("%s %v", b, err) }, "null <nil>"},
+		{"MarshalText", func() interface{} { b, err := e.MarshalText(); return fmt.Sprintf("%s %v", b, err) }, " <nil>"},
+		{"UnmarshalJSON", func() interface{} { return e.UnmarshalJSON([]byte(`{"op":"op"}`)) != nil }, true},
+		{"UnmarshalText", func() interface{} { return e.UnmarshalText([]byte(`op [op]`)) != nil }, true},
+		{"Op", func() interface{} { return e.Op() }, ""},
+		{"Kind", func() interface{} { return e.Kind() }, errors.K.Other},
+		{"Cause", func() interface{} { return e.Cause() }, nil},
+		{"UnderlyingCause", func() interface{} { return e.UnderlyingCause() }, nil},
+		{"WithOp", func() interface{} { return e.WithOp("op") }, e},
+		{"WithOpf", func() interface{} { return e.WithOpf("op %d", 1) }, e},
+		{"WithKind", func() interface{} { return e.WithKind(errors.K.IO) }, e},
+		{"WithDefaultKind", func() interface{} { return e.WithDefaultKind(errors.K.IO) }, e},
+		{"WithCode", func() interface{} { return e.WithCode("CODE") }, e},
+		{"WithCause", func() interface{} { return e.WithCause(io.EOF) }, e},
+		{"With", func() interface{} { return e.With("key", "val") }, e},
+		{"IsZero", func() interface{} { return e.IsZero() }, true},
+		{"Field", func() interface{} { return e.Field("op") }, nil},
+		{"FieldDeep", func() interface{} { return e.FieldDeep("op") }, nil},
+		{"FieldAll", func() interface{} { return e.FieldAll("op") }, []interface{}(nil)},
+		{"GetField", func() interface{} { v, ok := e.GetField("op"); return fmt.Sprintf("%s %v", v, ok) }, " false"},
+		{"Error", func() interface{} { return e.Error() }, ""},
+		{"ErrorNoTrace", func() interface{} { return e.ErrorNoTrace() }, ""},
+		{"ClearStacktrace", func() interface{} { return e.ClearStacktrace() }, e},
+		{"ReplaceField", func() interface{} { return e.ReplaceField("key", "val") }, e},
+		{"CopyFieldsTo", func() interface{} { return e.CopyFieldsTo(nil) }, e},
+		{"FormatError", func() interface{} { return e.FormatError(true) }, ""},
+		{"Summary", func() interface{} { return e.Summary() }, ""},
+		{"SummaryIn", func() interface{} { return e.SummaryIn("de") }, ""},
+		{"GoString", func() interface{} { return e.GoString() }, "(*errors.Error)(nil)"},
+		{"Equal", func() interface{} { return e.Equal(nil) }, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NotPanics(t, func() {
+				require.Equal(t, test.want, test.fn())
+			})
+		})
+	}
+
+	// nil *Error values nested in a cause chain
+	err := errors.NoTrace("op").WithCause(e)
+	require.Nil(t, err.Field("missing"))
+	require.Equal(t, []interface{}{"op"}, err.FieldAll("op"))
+}
+
+func TestError_IsZero(t *testing.T) {
+	var nilErr *errors.Error
+	require.True(t, nilErr.IsZero())
+	require.True(t, errors.E().IsZero())
+	require.True(t, errors.NoTrace().IsZero())
+	require.True(t, errors.NoTrace(nil).IsZero())
+
+	require.False(t, errors.NoTrace("op").IsZero())
+	require.False(t, errors.NoTrace(errors.K.Invalid).IsZero())
+	require.False(t, errors.NoTrace(io.EOF).IsZero())
+	require.False(t, errors.NoTrace().With("key", "val").IsZero())
+}
+
+func TestDepth(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{io.EOF, 0},
+		{(*errors.Error)(nil), 0},
+		{errors.NoTrace(), 1},
+		{errors.NoTrace("op", io.EOF), 1},
+		{errors.NoTrace("op", errors.NoTrace("nested")), 2},
+		{errors.NoTrace("op", errors.NoTrace("nested", errors.NoTrace("root", io.EOF))), 3},
+		{errors.NoTrace("op").WithCause((*errors.Error)(nil)), 1},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.err), func(t *testing.T) {
+			require.Equal(t, test.want, errors.Depth(test.err))
+			if e, ok := test.err.(*errors.Error); ok {
+				require.Equal(t, test.want, e.Depth())
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	root := errors.NoTrace("root", io.EOF)
+	nested := errors.NoTrace("nested", root)
+	err := errors.NoTrace("op", nested)
+
+	require.Equal(t, []error{err, nested, root, io.EOF}, err.Chain())
+	require.Equal(t, []error{err, nested, root, io.EOF}, errors.Chain(err))
+	leaf := errors.NoTrace("leaf").WithCause((*errors.Error)(nil))
+	require.Equal(t, []error{leaf}, leaf.Chain())
+	require.Equal(t, []error{leaf}, errors.Chain(leaf))
+
+	// the package-level function also unwraps other error types
+	wrapped := fmt.Errorf("wrapped: %w", root)
+	err = errors.NoTrace("op", wrapped)
+	require.Equal(t, []error{err, wrapped}, err.Chain())
+	require.Equal(t, []error{err, wrapped, root, io.EOF}, errors.Chain(err))
+
+	require.Nil(t, (*errors.Error)(nil).Chain())
+	require.Nil(t, errors.Chain(nil))
+	require.Nil(t, errors.Chain((*errors.Error)(nil)))
+	require.Equal(t, []error{io.EOF}, errors.Chain(io.EOF))
+}
+
+func TestError_OrNil(t *testing.T) {
+	var e *errors.Error
+	var err error = e
+	require.True(t, err != nil) // the footgun
+	require.True(t, e.OrNil() == nil)
+	require.True(t, errors.E().OrNil() == nil)
+
+	e = errors.NoTrace("op")
+	require.Equal(t, error(e), e.OrNil())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.True(t, errors.FromContext(ctx, "check").OrNil() == nil)
+	cancel()
+	require.True(t, errors.IsKind(errors.K.Cancelled, errors.FromContext(ctx, "check").OrNil()))
+}
+
+func TestIsEmpty(t *testing.T) {
+	var nilErr *errors.Error
+	var nilList *errors.ErrorList
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, true},
+		{errors.NilError, true},
+		{nilErr, true},
+		{errors.E(), true},
+		{nilList, true},
+		{&errors.ErrorList{}, true},
+		{io.EOF, false},
+		{errors.Str(""), false},
+		{errors.E("op"), false},
+		{errors.E(errors.E()), false},
+		{&errors.ErrorList{Errors: []error{io.EOF}}, false},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprint(idx, test.err), func(t *testing.T) {
+			require.Equal(t, test.want, errors.IsEmpty(test.err))
+		})
+	}
+}
+
+func TestError_CopyFieldsTo(t *testing.T) {
+	var nilErr *errors.Error
+	require.Nil(t, nilErr.CopyFieldsTo(nil))
+	require.Nil(t, errors.NoTrace("op", "k", "v").CopyFieldsTo(nil))
+
+	dst := errors.NoTrace("dst", errors.K.Invalid)
+	require.Same(t, dst, nilErr.CopyFieldsTo(dst))
+	require.Equal(t, "op [dst] kind [invalid]", dst.Error())
+
+	src := errors.NoTrace("src", errors.K.IO, errors.NoTrace("nested", "k3", "v3"), "k1", "v1", "k2", 2)
+	dst = errors.NoTrace("dst", errors.K.Invalid, io.EOF, "k2", "old", "k0", "v0")
+	require.Same(t, dst, src.CopyFieldsTo(dst))
+	require.Equal(t, "op [dst] kind [invalid] k2 [2] k0 [v0] k1 [v1] cause [EOF]", dst.Error())
+
+	// the source is unchanged
+	require.Equal(t, "op [src] kind [I/O error] k1 [v1] k2 [2] cause:\n\top [nested] kind [unclassified error] k3 [v3]", src.Error())
+}
+
+func TestCauseOfKind(t *testing.T) {
+	require.Nil(t, errors.CauseOfKind(nil, errors.K.IO))
+	require.Nil(t, errors.CauseOfKind(io.EOF, errors.K.IO))
+
+	timeout := errors.NoTrace("dial", errors.K.Timeout, io.EOF, "duration", "5s")
+	e2 := errors.NoTrace("connect", errors.K.IO, timeout)
+	e3 := errors.NoTrace("fetch", e2)
+
+	require.Same(t, timeout, errors.CauseOfKind(e3, errors.K.Timeout))
+	require.Same(t, timeout, e3.CauseOfKind(errors.K.Timeout))
+	require.Equal(t, "5s", e3.CauseOfKind(errors.K.Timeout).Field("duration"))
+	require.Same(t, e2, errors.CauseOfKind(e3, errors.K.IO))
+	require.Same(t, e2, errors.CauseOfKind(e2, errors.K.IO))
+
+	// e3 inherits the kind I/O error, but doesn't have it explicitly
+	require.Equal(t, errors.K.IO, e3.Kind())
+	require.Nil(t, errors.CauseOfKind(e3, errors.K.Other))
+	require.Nil(t, errors.CauseOfKind(e3, errors.K.NotExist))
+
+	var nilErr *errors.Error
+	require.Nil(t, nilErr.CauseOfKind(errors.K.IO))
+}
+
+func TestTransitTime(t *testing.T) {
+	sent := time.Date(2021, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	remote := errors.NoTrace("read", errors.K.IO, io.EOF).WithTimestamp(sent)
+
+	// JSON round trip
+	bts, err := json.Marshal(remote)
+	require.NoError(t, err)
+	require.Contains(t, string(bts), `"timestamp":"2021-01-02T03:04:05.123456789Z"`)
+	var unmarshalled errors.Error
+	require.NoError(t, json.Unmarshal(bts, &unmarshalled))
+
+	local := errors.NoTrace("fetch", &unmarshalled).WithTimestamp(sent.Add(1500 * time.Millisecond))
+	d, ok := errors.TransitTime(errors.NoTrace("process", local))
+	require.True(t, ok)
+	require.Equal(t, 1500*time.Millisecond, d)
+
+	for _, err := range []error{nil, io.EOF, remote, errors.NoTrace("fetch", remote)} {
+		d, ok = errors.TransitTime(err)
+		require.False(t, ok)
+		require.Equal(t, time.Duration(0), d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := errors.RetryAfter(nil)
+	require.False(t, ok)
+	require.Zero(t, d)
+
+	_, ok = errors.RetryAfter(io.EOF)
+	require.False(t, ok)
+
+	_, ok = errors.RetryAfter(errors.NoTrace("op"))
+	require.False(t, ok)
+
+	_, ok = errors.RetryAfter(errors.NoTrace("op", "retry_after", "soon"))
+	require.False(t, ok)
+
+	inner := errors.NoTrace("request", errors.K.Unavailable).WithRetryAfter(30 * time.Second)
+	require.Equal(t, "op [request] kind [service unavailable] retry_after [30s]", inner.Error())
+	err := errors.NoTrace("fetch", inner)
+
+	d, ok = errors.RetryAfter(err)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, d)
+
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	var unmarshalled errors.Error
+	require.NoError(t, json.Unmarshal(jsn, &unmarshalled))
+	d, ok = errors.RetryAfter(&unmarshalled)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, d)
+}
+
+func TestWithBytes(t *testing.T) {
+	hash := []byte{0x01, 0xab, 0xff}
+	err := errors.NoTrace("verify", errors.K.Invalid).WithBytes("hash", hash).With("nonce", []byte{0x10})
+	hash[0] = 0x00 // WithBytes copies the value
+	require.Equal(t, "op [verify] kind [invalid] hash [01abff] nonce [10]", err.Error())
+
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, `{"op":"verify","kind":"invalid","hash":"Aav/","nonce":"EA=="}`, string(jsn))
+
+	require.Nil(t, (*errors.Error)(nil).WithBytes("hash", hash))
+}
+
+func TestWithTimeAndDuration(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 500, time.UTC)
+	err := errors.NoTrace("expire", errors.K.Invalid).
+		WithTime("at", ts).
+		WithDuration("ttl", 90*time.Second).
+		With("since", ts.Add(time.Hour), "elapsed", time.Millisecond)
+	require.Equal(t, "op [expire] kind [invalid] at [2024-03-01T12:30:00.0000005Z] ttl [1m30s] since [2024-03-01T13:30:00.0000005Z] elapsed [1ms]", err.Error())
+
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, `{"op":"expire","kind":"invalid","at":"2024-03-01T12:30:00.0000005Z","ttl":"1m30s","since":"2024-03-01T13:30:00.0000005Z","elapsed":"1ms"}`, string(jsn))
+
+	require.Nil(t, (*errors.Error)(nil).WithTime("at", ts))
+	require.Nil(t, (*errors.Error)(nil).WithDuration("ttl", time.Second))
+}
+
 func TestGetRoot(t *testing.T) {
 	var e interface{}
 	require.Nil(t, errors.GetRoot(e))
@@ -480,6 +1207,19 @@ func TestGetRootCause(t *testing.T) {
 	require.Equal(t, errors.NilError, errors.GetRootCause(e))
 }
 
+func TestSameRootCause(t *testing.T) {
+	require.True(t, errors.SameRootCause(nil, nil))
+	require.True(t, errors.SameRootCause(errors.NoTrace("op1"), errors.NoTrace("op2", errors.NoTrace("op3"))))
+	require.True(t, errors.SameRootCause(io.EOF, errors.NoTrace("op1", io.EOF)))
+	require.True(t, errors.SameRootCause(
+		errors.NoTrace("op1", errors.K.IO, io.EOF),
+		errors.NoTrace("op2", errors.NoTrace("op3", errors.K.Invalid, errors.Str("EOF")))))
+
+	require.False(t, errors.SameRootCause(errors.NoTrace("op1"), errors.NoTrace("op1", io.EOF)))
+	require.False(t, errors.SameRootCause(io.EOF, nil))
+	require.False(t, errors.SameRootCause(errors.NoTrace("op1", io.EOF), errors.NoTrace("op1", io.ErrUnexpectedEOF)))
+}
+
 func TestWrap(t *testing.T) {
 	assert.Nil(t, errors.Wrap(nil))
 	assert.Nil(t, errors.Wrap(nil, "key", "value"))
@@ -492,6 +1232,328 @@ func TestWrap(t *testing.T) {
 	assert.Equal(t, "op [read] kind [invalid] key [val] cause [bad weather]", errors.Wrap(err, "key", "val").Error())
 }
 
+func TestCoerce(t *testing.T) {
+	require.Nil(t, errors.Coerce(nil))
+	require.Nil(t, errors.Coerce((*errors.Error)(nil)))
+	require.Nil(t, errors.Coerce((*errors.ErrorList)(nil)))
+
+	e := errors.NoTrace("read", errors.K.IO)
+	require.Same(t, e, errors.Coerce(e))
+
+	err := errors.Coerce(io.EOF)
+	require.Equal(t, io.EOF, err.Cause())
+	require.Equal(t, "kind [unclassified error] cause [EOF]", err.ErrorNoTrace())
+
+	list := errors.Append(e, io.EOF)
+	err = errors.Coerce(list)
+	require.Equal(t, list, err.Cause())
+	require.True(t, errors.Is(err, io.EOF))
+}
+
+func TestEnsureKind(t *testing.T) {
+	require.Nil(t, errors.EnsureKind(nil, errors.K.IO))
+	require.Nil(t, errors.EnsureKind((*errors.Error)(nil), errors.K.IO))
+
+	err := errors.NoTrace("read", errors.K.Invalid)
+	require.Same(t, err, errors.EnsureKind(err, errors.K.IO))
+
+	err = errors.NoTrace("read", errors.NoTrace("parse"))
+	res := errors.EnsureKind(err, errors.K.IO)
+	require.True(t, errors.IsKind(errors.K.IO, res))
+	require.Equal(t, errors.K.Other, err.Kind())
+	require.Equal(t, "read", res.(*errors.Error).Op())
+
+	res = errors.EnsureKind(io.EOF, errors.K.IO)
+	require.True(t, errors.IsKind(errors.K.IO, res))
+	require.Equal(t, io.EOF, res.(*errors.Error).Cause())
+}
+
+func TestWrapf(t *testing.T) {
+	assert.Nil(t, errors.Wrapf(nil, "read"))
+	assert.Nil(t, errors.Wrapf(nil, "read", errors.K.IO, "file", "a.txt"))
+
+	err := errors.Wrapf(io.EOF, "read", "file", "a.txt")
+	assert.Equal(t, "op [read] kind [unclassified error] file [a.txt] cause [EOF]", err.Error())
+
+	err = errors.Wrapf(errors.NoTrace("open", errors.K.NotExist), "read", "file", "a.txt")
+	assert.Equal(t, errors.K.NotExist, err.Kind())
+	assert.Equal(t, "read", err.Op())
+
+	err = errors.Wrapf(errors.NoTrace("open", errors.K.NotExist), "read", errors.K.Invalid, "file", "a.txt")
+	assert.Equal(t, errors.K.Invalid, err.Kind())
+	assert.Equal(t, "a.txt", err.Field("file"))
+}
+
+func TestWrap_sharedError(t *testing.T) {
+	sentinel := errors.NoTrace("lookup", errors.K.NotExist)
+
+	err1 := errors.Wrap(sentinel, "user", "joe")
+	err2 := errors.Wrap(sentinel, "user", "jane", "attempt", 2)
+
+	assert.Equal(t, "op [lookup] kind [item does not exist]", sentinel.Error())
+	assert.Equal(t, "op [lookup] kind [item does not exist] user [joe]", err1.Error())
+	assert.Equal(t, "op [lookup] kind [item does not exist] user [jane] attempt [2]", err2.Error())
+	assert.True(t, errors.IsKind(errors.K.NotExist, err1))
+	assert.True(t, errors.Is(err1, sentinel))
+	assert.True(t, errors.Is(errors.E("get user", err2), sentinel))
+	assert.False(t, errors.Is(sentinel, err1))
+	assert.False(t, errors.Is(errors.NoTrace("lookup", errors.K.NotExist), sentinel))
+}
+
+func TestError_Clone(t *testing.T) {
+	err := errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt")
+	clone := err.Clone().With("attempt", 1).WithKind(errors.K.Timeout)
+	assert.Equal(t, "op [read] kind [I/O error] file [a.txt] cause [EOF]", err.Error())
+	assert.Equal(t, "op [read] kind [operation timed out] file [a.txt] attempt [1] cause [EOF]", clone.Error())
+	assert.Nil(t, (*errors.Error)(nil).Clone())
+}
+
+func TestError_AdoptCauseKind(t *testing.T) {
+	cause := errors.NoTrace("read", errors.K.NotExist, "path", "/a/b")
+	err := errors.NoTrace("get user", cause).AdoptCauseKind()
+	require.Equal(t, errors.K.NotExist, err.Kind())
+
+	err.WithCause(errors.Str("internal details removed"))
+	require.Equal(t, "op [get user] kind [item does not exist] cause [internal details removed]", err.Error())
+
+	// explicit kind is replaced
+	err = errors.NoTrace("get user", errors.K.Invalid, cause).AdoptCauseKind()
+	require.Equal(t, errors.K.NotExist, err.Kind())
+
+	// default kind is used if the cause is unclassified
+	err = errors.NoTrace("get user", errors.K.IO.Default(), errors.NoTrace("inner")).AdoptCauseKind()
+	require.Equal(t, errors.K.IO, err.Kind())
+	err = errors.NoTrace("get user", errors.K.IO.Default(), cause).AdoptCauseKind()
+	require.Equal(t, errors.K.NotExist, err.Kind())
+
+	// no *Error cause
+	err = errors.NoTrace("get user", errors.K.Invalid, io.EOF).AdoptCauseKind()
+	require.Equal(t, errors.K.Invalid, err.Kind())
+	err = errors.NoTrace("get user").AdoptCauseKind()
+	require.Equal(t, errors.K.Other, err.Kind())
+
+	require.Nil(t, (*errors.Error)(nil).AdoptCauseKind())
+}
+
+func TestError_ReclassifyRoot(t *testing.T) {
+	err := errors.NoTrace("fetch", errors.NoTrace("read", errors.K.IO, io.EOF))
+	res := err.ReclassifyRoot(errors.K.NotExist)
+	require.Equal(t, "op [fetch] kind [item does not exist] cause:\n\top [read] kind [item does not exist] cause [EOF]",
+		res.Error())
+	require.Equal(t, "op [fetch] kind [I/O error] cause:\n\top [read] kind [I/O error] cause [EOF]", err.Error())
+
+	res = errors.NoTrace("read", errors.K.IO).ReclassifyRoot(errors.K.Invalid)
+	require.Equal(t, errors.K.Invalid, res.Kind())
+
+	require.Nil(t, (*errors.Error)(nil).ReclassifyRoot(errors.K.Invalid))
+}
+
+func TestError_ReclassifyWhere(t *testing.T) {
+	err := errors.NoTrace("fetch", errors.K.Unavailable,
+		errors.NoTrace("read", errors.NoTrace("open", errors.K.IO, io.EOF)))
+	res := err.ReclassifyWhere(func(e *errors.Error) bool { return e.Op() == "read" }, errors.K.Permission)
+	require.Equal(t, errors.K.Unavailable, res.Kind())
+	require.Equal(t, errors.K.Permission, res.Cause().(*errors.Error).Kind())
+	require.Equal(t, errors.K.IO, errors.GetRoot(res).Kind())
+	require.Equal(t, errors.K.IO, err.Cause().(*errors.Error).Kind())
+
+	require.Equal(t, err, err.ReclassifyWhere(nil, errors.K.Permission))
+}
+
+func TestWrapBounded(t *testing.T) {
+	require.Nil(t, errors.WrapBounded(nil, 3, "op"))
+
+	var err error = io.EOF
+	for i := 0; i < 5; i++ {
+		err = errors.NoTrace("attempt", errors.K.IO, err, "n", i)
+	}
+	original := err.Error()
+
+	tests := []struct {
+		maxDepth  int
+		wantDepth int
+		want      string
+	}{
+		{
+			maxDepth:  10,
+			wantDepth: 6,
+			want: "op [retry] kind [I/O error] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [4] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [3] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [2] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [1] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [0] cause [EOF]",
+		},
+		{
+			maxDepth:  6,
+			wantDepth: 6,
+			want: "op [retry] kind [I/O error] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [4] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [3] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [2] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [1] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [0] cause [EOF]",
+		},
+		{
+			maxDepth:  5,
+			wantDepth: 5,
+			want: "op [retry] kind [I/O error] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [4] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [3] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [2] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [0] collapsed [1] cause [EOF]",
+		},
+		{
+			maxDepth:  3,
+			wantDepth: 3,
+			want: "op [retry] kind [I/O error] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [4] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [0] collapsed [3] cause [EOF]",
+		},
+		{
+			maxDepth:  2,
+			wantDepth: 2,
+			want: "op [retry] kind [I/O error] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [0] collapsed [4] cause [EOF]",
+		},
+		{
+			maxDepth:  0,
+			wantDepth: 2,
+			want: "op [retry] kind [I/O error] cause:\n\t" +
+				"op [attempt] kind [I/O error] n [0] collapsed [4] cause [EOF]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.maxDepth), func(t *testing.T) {
+			bounded := errors.WrapBounded(err, test.maxDepth, "retry")
+			require.Equal(t, test.want, bounded.Error())
+			require.Equal(t, test.wantDepth, bounded.Depth())
+			require.Equal(t, original, err.Error())
+		})
+	}
+
+	// bounded in a retry loop
+	err = io.EOF
+	for i := 0; i < 100; i++ {
+		err = errors.WrapBounded(err, 3, "attempt", "n", i)
+		require.LessOrEqual(t, errors.Depth(err), 3)
+	}
+	require.Equal(t, "op [attempt] kind [unclassified error] n [99] cause:\n\t"+
+		"op [attempt] kind [unclassified error] n [98] cause:\n\t"+
+		"op [attempt] kind [unclassified error] n [0] collapsed [97] cause [EOF]",
+		errors.ClearStacktrace(err).Error())
+
+	// non-*Error causes are wrapped unchanged
+	require.Equal(t, "op [retry] kind [unclassified error] cause [EOF]", errors.WrapBounded(io.EOF, 1, "retry").ErrorNoTrace())
+}
+
+func TestWrapBounded_unmarshalled(t *testing.T) {
+	var err error = io.EOF
+	for i := 0; i < 5; i++ {
+		err = errors.NoTrace("attempt", errors.K.IO, err, "n", i)
+	}
+	err = errors.WrapBounded(err, 3, "retry")
+
+	// the collapsed count is a float64 after a JSON round trip
+	bts, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	var unmarshalled errors.Error
+	require.NoError(t, json.Unmarshal(bts, &unmarshalled))
+
+	bounded := errors.WrapBounded(&unmarshalled, 1, "retry")
+	collapsed, _ := errors.GetRoot(bounded).GetField("collapsed")
+	require.Equal(t, "5", collapsed)
+}
+
+func TestWrapBounded_counter(t *testing.T) {
+	counter := errors.NewMapKindCounter()
+	errors.SetKindCounter(counter)
+	defer errors.SetKindCounter(nil)
+
+	_ = errors.WrapBounded(errors.NoTrace("attempt", errors.K.IO, io.EOF), 1, "retry")
+	require.Equal(t, map[errors.Kind]int64{errors.K.IO: 2}, counter.Snapshot())
+}
+
+type netError struct {
+	timeout   bool
+	temporary bool
+}
+
+func (e *netError) Error() string   { return "net error" }
+func (e *netError) Timeout() bool   { return e.timeout }
+func (e *netError) Temporary() bool { return e.temporary }
+
+func TestCapture(t *testing.T) {
+	assert.Nil(t, errors.Capture(nil))
+	assert.Nil(t, errors.Capture(nil, "op", errors.K.IO))
+
+	tests := []struct {
+		err  *errors.Error
+		want string
+	}{
+		{errors.Capture(io.EOF), "kind [unclassified error] cause [EOF]"},
+		{errors.Capture(io.EOF, "read", errors.K.IO, "file", "a.txt"), "op [read] kind [I/O error] file [a.txt] cause [EOF]"},
+		{
+			errors.Capture(&netError{timeout: true}, "dial"),
+			"op [dial] kind [unclassified error] temporary [false] timeout [true] cause [net error]",
+		},
+		{
+			errors.Capture(fmt.Errorf("wrapped: %w", &netError{temporary: true}), "dial"),
+			"op [dial] kind [unclassified error] temporary [true] timeout [false] cause [wrapped: net error]",
+		},
+		{
+			errors.Capture(context.DeadlineExceeded, "query"),
+			"op [query] kind [unclassified error] temporary [true] timeout [true] cause [context deadline exceeded]",
+		},
+		{
+			errors.Capture(fmt.Errorf("query: %w", context.Canceled)),
+			"kind [unclassified error] canceled [true] cause [query: context canceled]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, test.err.ErrorNoTrace())
+		})
+	}
+
+	// the fields survive a JSON round-trip, while the cause's type is lost
+	jsn, err := json.Marshal(errors.Capture(&netError{timeout: true}, "dial"))
+	require.NoError(t, err)
+	var unmarshalled errors.Error
+	require.NoError(t, json.Unmarshal(jsn, &unmarshalled))
+	require.Equal(t, true, unmarshalled.Field("timeout"))
+}
+
+func TestCapture_OnCreate(t *testing.T) {
+	defer func() { errors.OnCreate = nil }()
+
+	var seen *errors.Error
+	errors.OnCreate = func(e *errors.Error) {
+		seen = e
+	}
+
+	counter := errors.NewMapKindCounter()
+	errors.SetKindCounter(counter)
+	defer errors.SetKindCounter(nil)
+
+	err := errors.Capture(errors.NoTrace("dial", errors.K.IO, &netError{timeout: true}), "fetch")
+	require.Same(t, err, seen)
+	timeout, _ := seen.GetField("timeout")
+	require.Equal(t, "true", timeout)
+	require.Equal(t, map[errors.Kind]int64{errors.K.IO: 2}, counter.Snapshot())
+}
+
+func TestCapture_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	s := errors.Capture(io.EOF, "read").Error()
+	assert.NotContains(t, s, "Capture()")
+	assert.Contains(t, s, "TestCapture_Stacktrace()")
+}
+
 func TestIgnore(t *testing.T) {
 	errors.Ignore(nil) // ensure no crash
 
@@ -543,6 +1605,17 @@ func TestTypeOf(t *testing.T) {
 	assert.Equal(t, "int64", errors.TypeOf(int64(0)))
 }
 
+func TestUnexpected(t *testing.T) {
+	err := errors.Unexpected("state", 3, "job", 42)
+	assert.Equal(t, "op [unexpected] kind [internal error] what [state] value [int(3)] job [42]",
+		errors.ClearStacktrace(err).Error())
+
+	err = errors.Unexpected("response", nil)
+	assert.Equal(t, "op [unexpected] kind [internal error] what [response] value [<nil>(<nil>)]",
+		errors.ClearStacktrace(err).Error())
+	assert.True(t, errors.IsKind(errors.K.Internal, err))
+}
+
 func TestError_Kind(t *testing.T) {
 	tests := []struct {
 		want interface{}
@@ -560,6 +1633,153 @@ func TestError_Kind(t *testing.T) {
 	}
 }
 
+func TestEf(t *testing.T) {
+	err := errors.Ef("load chunk %d of %s", 3, "file.txt")
+	assert.Equal(t, "load chunk 3 of file.txt", err.Op())
+	assert.Equal(t, "op [load chunk 3 of file.txt] kind [unclassified error]", err.Error())
+
+	err = errors.Ef("load %s", "config").WithKind(errors.K.IO).WithCause(io.EOF)
+	assert.Equal(t, "op [load config] kind [I/O error] cause [EOF]", err.Error())
+
+	assert.Equal(t, "kind [unclassified error]", errors.Ef("").Error())
+}
+
+func TestEf_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	s := errors.Ef("load chunk %d", 3).Error()
+	assert.NotContains(t, s, "Ef()")
+	assert.Contains(t, s, "TestEf_Stacktrace()")
+}
+
+func TestErrorf(t *testing.T) {
+	err := errors.Errorf("load config %s: %w", "a.txt", errors.NoTrace("read", errors.K.IO, io.EOF))
+	assert.Equal(t, "", err.Op())
+	assert.Equal(t, errors.K.IO, err.Kind())
+	assert.Equal(t, "load config a.txt: op [read] kind [I/O error] cause [EOF]", err.Field("message"))
+	assert.Equal(t, "read", err.Cause().(*errors.Error).Op())
+
+	err = errors.Errorf("load config %s: %w", "a.txt", io.EOF)
+	assert.Equal(t, "kind [unclassified error] message [load config a.txt: EOF] cause [EOF]", err.Error())
+	assert.Equal(t, io.EOF, err.Cause())
+
+	err = errors.Errorf("load config %s at 100%%", "a.txt")
+	assert.Equal(t, "kind [unclassified error] message [load config a.txt at 100%]", err.Error())
+	assert.Nil(t, err.Cause())
+
+	err = errors.Errorf("%w and %w", io.EOF, io.ErrUnexpectedEOF)
+	assert.Equal(t, "op [errorf] kind [invalid] reason [multiple %w verbs] format [%w and %w]", err.Error())
+}
+
+func TestErrorf_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	s := errors.Errorf("load chunk %d", 3).Error()
+	assert.NotContains(t, s, "Errorf()")
+	assert.Contains(t, s, "TestErrorf_Stacktrace()")
+
+	// the message of a cause with stacktrace does not contain the stacktrace
+	cause := errors.E("read", errors.K.IO, io.EOF)
+	err := errors.Errorf("load config %s: %w", "a.txt", cause)
+	assert.Equal(t, "load config a.txt: op [read] kind [I/O error] cause [EOF]", err.Field("message"))
+	assert.Same(t, cause, err.Cause())
+	assert.Contains(t, err.Error(), "TestErrorf_Stacktrace()")
+}
+
+func TestError_WithOpf(t *testing.T) {
+	err := errors.E("op", errors.K.IO).WithOpf("read %d bytes", 10)
+	assert.Equal(t, "read 10 bytes", err.Op())
+
+	// an empty op does not override the existing op
+	err = errors.E("op").WithOpf("")
+	assert.Equal(t, "op", err.Op())
+}
+
+func TestError_WithCauseString(t *testing.T) {
+	err := errors.NoTrace("sync", errors.K.Unavailable).WithCauseString("upstream: quota exceeded")
+	require.Equal(t, "op [sync] kind [service unavailable] cause [upstream: quota exceeded]", err.Error())
+	require.Equal(t, errors.Str("upstream: quota exceeded"), errors.GetRootCause(err))
+
+	require.Nil(t, errors.NoTrace("sync").WithCauseString("").Cause())
+	require.Nil(t, (*errors.Error)(nil).WithCauseString("msg"))
+}
+
+func TestError_WithCause_cycle(t *testing.T) {
+	e := errors.NoTrace("op", errors.K.IO)
+	require.Equal(t, e, e.WithCause(e))
+	require.Nil(t, e.Cause())
+	require.Equal(t, "op [op] kind [I/O error] cycle_detected [true]", e.Error())
+
+	// indirect cycle
+	inner := errors.NoTrace("inner", io.EOF)
+	outer := errors.NoTrace("outer", inner)
+	_ = inner.With(outer)
+	require.Equal(t, io.EOF, inner.Cause())
+	require.Equal(t, true, inner.Field("cycle_detected"))
+	require.Equal(t, "op [outer] kind [unclassified error] cause:\n\top [inner] kind [unclassified error] cycle_detected [true] cause [EOF]", outer.Error())
+
+	// cycle through a non-*Error wrapper
+	inner = errors.NoTrace("inner")
+	_ = inner.WithCause(fmt.Errorf("wrapped: %w", errors.NoTrace("outer", inner)))
+	require.Nil(t, inner.Cause())
+	require.Equal(t, true, inner.Field("cycle_detected"))
+
+	// cycle through an error list
+	inner = errors.NoTrace("inner")
+	_ = inner.WithCause(errors.NoTrace("outer", errors.Append(inner, io.EOF)))
+	require.Nil(t, inner.Cause())
+	require.Equal(t, true, inner.Field("cycle_detected"))
+
+	// cycle through a multi-error of the standard library
+	inner = errors.NoTrace("inner")
+	_ = inner.WithCause(fmt.Errorf("joined: %w", stderrors.Join(io.EOF, errors.NoTrace("outer", inner))))
+	require.Nil(t, inner.Cause())
+	require.Equal(t, true, inner.Field("cycle_detected"))
+}
+
+func TestError_WithCode(t *testing.T) {
+	defer resetDefaultFieldOrder()()
+
+	err := errors.E("suspend", errors.K.Permission, io.EOF, "account", "acme").WithCode("ACCT_SUSPENDED")
+	assert.Equal(t, "op [suspend] kind [permission denied] code [ACCT_SUSPENDED] account [acme] cause [EOF]", err.Error())
+
+	jsn, jerr := json.Marshal(err.ClearStacktrace())
+	require.NoError(t, jerr)
+	assert.Equal(t, `{"op":"suspend","kind":"permission denied","code":"ACCT_SUSPENDED","account":"acme","cause":"EOF"}`, string(jsn))
+
+	var unmarshalled errors.Error
+	require.NoError(t, json.Unmarshal(jsn, &unmarshalled))
+	assert.Equal(t, "ACCT_SUSPENDED", errors.Code(&unmarshalled))
+
+	errors.DefaultFieldOrder = []string{"code", ""}
+	assert.Equal(t, "code [ACCT_SUSPENDED] op [suspend] kind [permission denied] account [acme] cause [EOF]", err.Error())
+
+	// empty code is ignored
+	assert.Equal(t, "", errors.Code(errors.E().WithCode("")))
+}
+
+func TestCode(t *testing.T) {
+	inner := errors.E("read", errors.K.IO).WithCode("DISK_FULL")
+	outer := errors.E("save", inner).WithCode("SAVE_FAILED")
+
+	assert.Equal(t, "", errors.Code(nil))
+	assert.Equal(t, "", errors.Code(io.EOF))
+	assert.Equal(t, "", errors.Code(errors.E("op")))
+	assert.Equal(t, "DISK_FULL", errors.Code(inner))
+	assert.Equal(t, "SAVE_FAILED", errors.Code(outer))
+	assert.Equal(t, "DISK_FULL", errors.Code(errors.E("save", inner)))
+
+	assert.False(t, errors.IsCode("DISK_FULL", nil))
+	assert.False(t, errors.IsCode("DISK_FULL", io.EOF))
+	assert.False(t, errors.IsCode("DISK_FULL", (*errors.Error)(nil)))
+	assert.True(t, errors.IsCode("DISK_FULL", inner))
+	assert.True(t, errors.IsCode("DISK_FULL", outer))
+	assert.True(t, errors.IsCode("SAVE_FAILED", outer))
+	assert.False(t, errors.IsCode("SAVE_FAILED", inner))
+}
+
 func TestError_OpKindCauseArgs(t *testing.T) {
 	errs := []*errors.Error{
 		errors.E().WithOp("operation").WithKind(errors.K.IO).WithCause(io.EOF),
@@ -616,6 +1836,127 @@ func TestError_Unwrap(t *testing.T) {
 	require.Nil(t, errors.E("noop").Unwrap())
 }
 
+func TestSummary(t *testing.T) {
+	var nilErr *errors.Error
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{nilErr, ""},
+		{io.EOF, "EOF"},
+		{errors.NoTrace(), "unclassified error"},
+		{errors.NoTrace(errors.K.Permission), "permission denied"},
+		{errors.NoTrace("download", errors.K.NotExist, "file", "a.txt"), "download failed: item does not exist"},
+		{errors.NoTrace("download", errors.K.IO, "message", "disk full"), "download failed: I/O error: disk full"},
+		{errors.NoTrace("download", errors.NoTrace("open", errors.K.NotExist)), "download failed: item does not exist"},
+		{errors.NoTrace("download", errors.NoTrace("open", errors.K.NotExist).WithMessage("no such file")), "download failed: item does not exist: no such file"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, errors.Summary(test.err))
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	require.Equal(t, "", errors.Message(nil))
+	require.Equal(t, "", errors.Message(io.EOF))
+	require.Equal(t, "", errors.Message(errors.NoTrace("op")))
+
+	inner := errors.NoTrace("read", errors.K.IO).WithMessage("disk full")
+	require.Equal(t, "op [read] kind [I/O error] message [disk full]", inner.Error())
+	require.Equal(t, "disk full", errors.Message(inner))
+	require.Equal(t, "disk full", errors.Message(errors.NoTrace("write", inner)))
+	require.Equal(t, "quota exceeded", errors.Message(errors.NoTrace("write", inner).WithMessage("quota exceeded")))
+
+	// an empty message is ignored
+	require.Equal(t, "disk full", errors.Message(inner.WithMessage("")))
+	require.Nil(t, (*errors.Error)(nil).WithMessage("msg"))
+}
+
+func TestSummaryIn(t *testing.T) {
+	errors.RegisterKindTranslation("es", errors.K.NotExist, "el elemento no existe")
+
+	err := errors.NoTrace("download", errors.K.NotExist)
+	require.Equal(t, "download failed: el elemento no existe", err.SummaryIn("es"))
+	require.Equal(t, "download failed: el elemento no existe", errors.SummaryIn(err, "es"))
+	require.Equal(t, "download failed: item does not exist", errors.SummaryIn(err, "pt"))
+	require.Equal(t, "download failed: item does not exist", err.Summary())
+	require.Equal(t, "EOF", errors.SummaryIn(io.EOF, "es"))
+}
+
+func TestError_GoString(t *testing.T) {
+	var nilErr *errors.Error
+	tests := []struct {
+		err  *errors.Error
+		want string
+	}{
+		{nilErr, `(*errors.Error)(nil)`},
+		{errors.NoTrace(), `errors.E()`},
+		{errors.NoTrace("op"), `errors.E("op")`},
+		{errors.NoTrace(errors.K.Invalid), `errors.E(errors.K.Invalid)`},
+		{errors.NoTrace(errors.Kind("custom kind")), `errors.E(errors.Kind("custom kind"))`},
+		{errors.NoTrace(errors.K.IO.Default()), `errors.E(errors.K.IO.Default())`},
+		{errors.NoTrace("op", "key"), `errors.E("op", "key", "<missing>")`},
+		{errors.NoTrace().With("k1", "v1"), `errors.E("", "k1", "v1")`},
+		{
+			errors.NoTrace("op", errors.K.Invalid, io.EOF, "k1", "v1", "k2", 2),
+			`errors.E("op", errors.K.Invalid, errors.Str("EOF"), "k1", "v1", "k2", 2)`,
+		},
+		{
+			errors.NoTrace("op", errors.K.Invalid, "path", "C:\\dir\n\"quoted\"", "ok", true),
+			`errors.E("op", errors.K.Invalid, "path", "C:\\dir\n\"quoted\"", "ok", true)`,
+		},
+		{
+			errors.NoTrace("op1", errors.NoTrace("op2", errors.K.NotExist, errors.NoTrace("op3", io.EOF), "user", "joe")),
+			`errors.E("op1", errors.E("op2", errors.K.NotExist, errors.E("op3", errors.Str("EOF")), "user", "joe"))`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, test.err.GoString())
+			require.Equal(t, test.want, fmt.Sprintf("%#v", test.err))
+		})
+	}
+
+	// the stacktrace is omitted
+	revert := enableStacktraces()
+	defer revert()
+	require.Equal(t, `errors.E("op", errors.K.IO)`, fmt.Sprintf("%#v", errors.E("op", errors.K.IO)))
+}
+
+func TestError_UnderlyingCause(t *testing.T) {
+	var nilErr *errors.Error
+	require.Nil(t, nilErr.UnderlyingCause())
+
+	meaningful := errors.E("read", errors.K.IO, io.EOF)
+	withFields := errors.E("key", "val", meaningful)
+	withKind := errors.E(errors.K.Invalid, meaningful)
+
+	tests := []struct {
+		name string
+		err  *errors.Error
+		want error
+	}{
+		{"no cause", errors.E("op"), nil},
+		{"std error", errors.E("op", io.EOF), io.EOF},
+		{"*Error", errors.E("op", meaningful), meaningful},
+		{"wrapped *Error", errors.E("op", errors.E(errors.E(meaningful))), meaningful},
+		{"wrapped std error", errors.E("op", errors.E(errors.E(io.EOF))), io.EOF},
+		{"wrapped zero error", errors.E("op", errors.E(errors.E())), nil},
+		{"wrapper with fields", errors.E("op", withFields), withFields},
+		{"wrapper with kind", errors.E("op", errors.E(withKind)), withKind},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, test.err.UnderlyingCause())
+		})
+	}
+}
+
 func TestError_FormatError(t *testing.T) {
 	var err *errors.Error
 	assert.Equal(t, "", err.Error())
@@ -628,6 +1969,57 @@ func TestError_FormatError(t *testing.T) {
 	assert.Equal(t, "k3 [v3] k2 [v2] op [op] kind [invalid] k1 [v1] cause [EOF]", err.FormatError(false, "k3", "k2"))
 }
 
+func TestKindBeforeOp(t *testing.T) {
+	defer func(kbo bool) {
+		errors.KindBeforeOp = kbo
+	}(errors.KindBeforeOp)
+
+	err := errors.NoTrace("op", errors.K.Invalid, errors.NoTrace("inner", io.EOF), "k1", "v1", "k2", "v2")
+
+	tests := []struct {
+		fieldOrder []string
+		want       string
+		wantKbo    string
+	}{
+		{
+			fieldOrder: nil,
+			want:       "op [op] kind [invalid] k1 [v1] k2 [v2] cause:\n\top [inner] kind [unclassified error] cause [EOF]",
+			wantKbo:    "kind [invalid] op [op] k1 [v1] k2 [v2] cause:\n\tkind [unclassified error] op [inner] cause [EOF]",
+		},
+		{
+			fieldOrder: []string{"k2", ""},
+			want:       "k2 [v2] op [op] kind [invalid] k1 [v1] cause:\n\top [inner] kind [unclassified error] cause [EOF]",
+			wantKbo:    "k2 [v2] kind [invalid] op [op] k1 [v1] cause:\n\tkind [unclassified error] op [inner] cause [EOF]",
+		},
+		{
+			// explicitly placed op and kind are not affected (nested errors are formatted with DefaultFieldOrder)
+			fieldOrder: []string{"op", "kind", ""},
+			want:       "op [op] kind [invalid] k1 [v1] k2 [v2] cause:\n\top [inner] kind [unclassified error] cause [EOF]",
+			wantKbo:    "op [op] kind [invalid] k1 [v1] k2 [v2] cause:\n\tkind [unclassified error] op [inner] cause [EOF]",
+		},
+		{
+			// only op is placed explicitly
+			fieldOrder: []string{"", "op"},
+			want:       "kind [invalid] k1 [v1] k2 [v2] cause:\n\top [inner] kind [unclassified error] cause [EOF] op [op]",
+			wantKbo:    "kind [invalid] k1 [v1] k2 [v2] cause:\n\tkind [unclassified error] op [inner] cause [EOF] op [op]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.fieldOrder), func(t *testing.T) {
+			errors.KindBeforeOp = false
+			require.Equal(t, test.want, err.FormatError(false, test.fieldOrder...))
+			errors.KindBeforeOp = true
+			require.Equal(t, test.wantKbo, err.FormatError(false, test.fieldOrder...))
+		})
+	}
+
+	errors.KindBeforeOp = true
+	jsn, jerr := json.Marshal(errors.NoTrace("op", errors.K.Invalid, "k1", "v1"))
+	require.NoError(t, jerr)
+	require.Equal(t, `{"kind":"invalid","op":"op","k1":"v1"}`, string(jsn))
+}
+
 func TestError_MarshalJSON(t *testing.T) {
 	revert := enableStacktraces()
 	defer revert()
@@ -699,6 +2091,43 @@ func TestError_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestError_MarshalJSONOrdered(t *testing.T) {
+	err := errors.NoTrace("op", errors.K.Invalid, errors.NoTrace("inner", io.EOF, "k3", "v3"), "k1", "v1", "k2", "v2")
+
+	b, jerr := err.MarshalJSONOrdered([]string{"k2", "kind", "", "op"})
+	require.NoError(t, jerr)
+	require.Equal(t,
+		`{"k2":"v2","kind":"invalid","k1":"v1","cause":{"kind":"unclassified error","k3":"v3","cause":"EOF","op":"inner"},"op":"op"}`,
+		string(b))
+
+	// the global default field order is not affected
+	b, jerr = json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t,
+		`{"op":"op","kind":"invalid","k1":"v1","k2":"v2","cause":{"op":"inner","kind":"unclassified error","k3":"v3","cause":"EOF"}}`,
+		string(b))
+
+	b, jerr = (*errors.Error)(nil).MarshalJSONOrdered(nil)
+	require.NoError(t, jerr)
+	require.Equal(t, "null", string(b))
+}
+
+func TestJSONString(t *testing.T) {
+	err := errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt")
+	require.Equal(t, `{"op":"read","kind":"I/O error","file":"a.txt","cause":"EOF"}`, err.JSONString())
+	require.Equal(t, err.JSONString(), errors.JSONString(err))
+	require.Equal(t, "{\n  \"op\": \"read\",\n  \"kind\": \"I/O error\",\n  \"file\": \"a.txt\",\n  \"cause\": \"EOF\"\n}", err.JSONStringIndent("  "))
+
+	require.Equal(t, `"EOF"`, errors.JSONString(io.EOF))
+	require.Equal(t, `{"errors":["EOF","unexpected EOF"]}`, errors.JSONString(errors.Append(io.EOF, io.ErrUnexpectedEOF)))
+	require.Equal(t, "null", errors.JSONString(nil))
+	require.Equal(t, "null", (*errors.Error)(nil).JSONString())
+
+	// marshaling failure
+	err = errors.NoTrace("read", "ch", make(chan int))
+	require.Equal(t, `{"error":"op [read] kind [unclassified error] ch [`+fmt.Sprint(err.Field("ch"))+`]","json_error":"json: error calling MarshalJSON for type *errors.Error: json: unsupported type: chan int"}`, err.JSONString())
+}
+
 func TestError_MarshalJSON_StackAsArray(t *testing.T) {
 	revert := enableMarshalStacktraceAsArray()
 	defer revert()
@@ -928,6 +2357,68 @@ func TestError_UnmarshalJSON_failures(t *testing.T) {
 	}
 }
 
+func TestError_UnmarshalJSON_nonObject(t *testing.T) {
+	tests := []struct {
+		json string
+		got  string
+	}{
+		{`["an","array"]`, "array"},
+		{` [ ]`, "array"},
+		{`"a string"`, "string"},
+		{`-12.5`, "number"},
+		{`42`, "number"},
+		{`true`, "boolean"},
+		{`false`, "boolean"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.json, func(t *testing.T) {
+			var e errors.Error
+			err := json.Unmarshal([]byte(test.json), &e)
+			require.Error(t, err)
+			require.True(t, errors.IsKind(errors.K.Invalid, err), err)
+			require.Equal(t, "unmarshal", errors.Field(err, "op"))
+			require.Equal(t, "expected JSON object", errors.Field(err, "reason"))
+			require.Equal(t, test.got, errors.Field(err, "got"))
+		})
+	}
+}
+
+func TestError_UnmarshalJSON_null(t *testing.T) {
+	var e errors.Error
+	require.NoError(t, json.Unmarshal([]byte(`null`), &e))
+	require.Equal(t, errors.Error{}, e)
+
+	e = *errors.NoTrace("op", errors.K.IO, "key", "val")
+	require.NoError(t, e.UnmarshalJSON([]byte(`null`)))
+	require.Equal(t, errors.Error{}, e)
+
+	var pe *errors.Error
+	require.NoError(t, json.Unmarshal([]byte(`null`), &pe))
+	require.Nil(t, pe)
+}
+
+func TestError_UnmarshalJSON_kindCode(t *testing.T) {
+	tests := []struct {
+		jsn  string
+		want string
+	}{
+		{`{"op":"read","kind_code":7}`, "op [read] kind [item does not exist]"},
+		{`{"op":"read","kind":"bogus","kind_code":7}`, "op [read] kind [item does not exist]"},
+		{`{"op":"read","kind_code":7,"kind":"bogus"}`, "op [read] kind [item does not exist]"},
+		{`{"op":"read","kind":"invalid","kind_code":999}`, "op [read] kind [invalid] kind_code [999]"},
+		{`{"op":"read","kind":"invalid","kind_code":"7"}`, "op [read] kind [invalid] kind_code [7]"},
+		{`{"op":"fetch","cause":{"op":"read","kind_code":5}}`, "op [fetch] kind [I/O error] cause:\n\top [read] kind [I/O error]"},
+	}
+	for _, test := range tests {
+		t.Run(test.jsn, func(t *testing.T) {
+			var e errors.Error
+			require.NoError(t, json.Unmarshal([]byte(test.jsn), &e))
+			require.Equal(t, test.want, e.ErrorNoTrace())
+		})
+	}
+}
+
 func TestMiddlewareError(t *testing.T) {
 	eList := []error{
 		createMoreNestedError(),
@@ -0,0 +1,64 @@
+// Package errorstest provides test helpers for asserting the kind, op and fields of errors created with the errors
+// package. The helpers stop the test with a descriptive failure message if the assertion fails:
+//
+//	err := Download(file)
+//	errorstest.RequireKind(t, errors.K.NotExist, err)
+//	errorstest.RequireOp(t, "download", err)
+//	errorstest.RequireField(t, err, "file", file)
+package errorstest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eluv-io/errors-go"
+)
+
+// RequireKind fails the test if err is not an *errors.Error of the given kind. Like errors.IsKind, the kind is matched
+// against err and all its nested causes.
+func RequireKind(t testing.TB, kind errors.Kind, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected error of kind [%s], got nil", kind)
+		return
+	}
+	if !errors.IsKind(kind, err) {
+		t.Fatalf("expected error of kind [%s], got kind [%v]\nerror: %s", kind, errors.Field(err, errors.KindKey), err)
+	}
+}
+
+// RequireOp fails the test if err is not an *errors.Error with the given op. The op is matched against err only and
+// not against its nested causes.
+func RequireOp(t testing.TB, op string, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected error with op [%s], got nil", op)
+		return
+	}
+	e, ok := err.(*errors.Error)
+	if !ok {
+		t.Fatalf("expected error with op [%s], got %T\nerror: %s", op, err, err)
+		return
+	}
+	if e.Op() != op {
+		t.Fatalf("expected error with op [%s], got op [%s]\nerror: %s", op, e.Op(), err)
+	}
+}
+
+// RequireField fails the test if err is not an *errors.Error with the given field value as returned by errors.Field.
+// Values are compared with reflect.DeepEqual.
+func RequireField(t testing.TB, err error, key string, val interface{}) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected error with field %s [%v], got nil", key, val)
+		return
+	}
+	actual := errors.Field(err, key)
+	if actual == nil {
+		t.Fatalf("expected error with field %s [%v], but field is missing\nerror: %s", key, val, err)
+		return
+	}
+	if !reflect.DeepEqual(val, actual) {
+		t.Fatalf("expected error with field %s [%#v] (%T), got [%#v] (%T)\nerror: %s", key, val, val, actual, actual, err)
+	}
+}
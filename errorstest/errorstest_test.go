@@ -0,0 +1,77 @@
+package errorstest_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+	"github.com/eluv-io/errors-go/errorstest"
+)
+
+// recorder is a testing.TB that records failures instead of stopping the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestRequireKind(t *testing.T) {
+	err := errors.NoTrace("download", errors.K.IO, errors.NoTrace("open", errors.K.NotExist))
+
+	errorstest.RequireKind(t, errors.K.IO, err)
+	errorstest.RequireKind(t, errors.K.NotExist, err)
+
+	r := &recorder{TB: t}
+	errorstest.RequireKind(r, errors.K.Permission, err)
+	errorstest.RequireKind(r, errors.K.Permission, io.EOF)
+	errorstest.RequireKind(r, errors.K.Permission, nil)
+	require.Equal(t, []string{
+		"expected error of kind [permission denied], got kind [I/O error]\nerror: " + err.Error(),
+		"expected error of kind [permission denied], got kind [<nil>]\nerror: EOF",
+		"expected error of kind [permission denied], got nil",
+	}, r.failures)
+}
+
+func TestRequireOp(t *testing.T) {
+	err := errors.NoTrace("download", errors.NoTrace("open"))
+
+	errorstest.RequireOp(t, "download", err)
+
+	r := &recorder{TB: t}
+	errorstest.RequireOp(r, "open", err)
+	errorstest.RequireOp(r, "open", io.EOF)
+	errorstest.RequireOp(r, "open", nil)
+	require.Equal(t, []string{
+		"expected error with op [open], got op [download]\nerror: " + err.Error(),
+		"expected error with op [open], got *errors.errorString\nerror: EOF",
+		"expected error with op [open], got nil",
+	}, r.failures)
+}
+
+func TestRequireField(t *testing.T) {
+	err := errors.NoTrace("download", errors.NoTrace("open", "file", "a.txt"), "user", "joe", "attempt", 2)
+
+	errorstest.RequireField(t, err, "user", "joe")
+	errorstest.RequireField(t, err, "attempt", 2)
+	errorstest.RequireField(t, err, "file", "a.txt")
+
+	r := &recorder{TB: t}
+	errorstest.RequireField(r, err, "user", "jane")
+	errorstest.RequireField(r, err, "attempt", int64(2))
+	errorstest.RequireField(r, err, "missing", "x")
+	errorstest.RequireField(r, nil, "user", "joe")
+	require.Equal(t, []string{
+		"expected error with field user [\"jane\"] (string), got [\"joe\"] (string)\nerror: " + err.Error(),
+		"expected error with field attempt [2] (int64), got [2] (int)\nerror: " + err.Error(),
+		"expected error with field missing [x], but field is missing\nerror: " + err.Error(),
+		"expected error with field user [joe], got nil",
+	}, r.failures)
+}
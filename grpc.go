@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"time"
+)
+
+// grpcCodes maps kinds to gRPC status codes as defined in google.golang.org/grpc/codes. Kinds that are not listed map to
+// 2 (Unknown).
+var grpcCodes = map[Kind]int{
+	K.Cancelled:      1,  // Canceled
+	K.Invalid:        3,  // InvalidArgument
+	K.Timeout:        4,  // DeadlineExceeded
+	K.NotExist:       5,  // NotFound
+	K.NotFound:       5,  // NotFound
+	K.Exist:          6,  // AlreadyExists
+	K.Permission:     7,  // PermissionDenied
+	K.Finalized:      9,  // FailedPrecondition
+	K.NotFinalized:   9,  // FailedPrecondition
+	K.NotImplemented: 12, // Unimplemented
+	K.Internal:       13, // Internal
+	K.NoNetRoute:     14, // Unavailable
+	K.Unavailable:    14, // Unavailable
+}
+
+// GRPCCode returns the gRPC status code corresponding to the kind of the given error as determined by Classify(). The
+// code is returned as int in order to avoid a dependency on the gRPC module and can be converted with codes.Code(c).
+// Kinds without a corresponding code map to 2 (Unknown). Returns 0 (OK) if err is nil.
+func GRPCCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, ok := grpcCodes[Classify(err)]; ok {
+		return code
+	}
+	return 2
+}
+
+// ToStatusDetail converts this error to a structure modelled after google.rpc.Status, which can be converted to a gRPC
+// status by the caller without requiring a dependency on protobuf in this package:
+//
+//	{
+//	  "code": 5,
+//	  "message": "get user failed: item does not exist",
+//	  "details": [
+//	    {"key": "op", "value": "get user"},
+//	    {"key": "kind", "value": "item does not exist"},
+//	    {"key": "user", "value": "u1"},
+//	    {"key": "cause.op", "value": "read"},
+//	    ...
+//	  ]
+//	}
+//
+// The code is determined with GRPCCode(), the message with Summary(). The details contain one entry per field of the
+// error and its nested errors in the same order and with the same keys as in AllFields(), except for stacktraces. Field
+// values are converted to types supported by structpb.NewStruct(): strings, bools and numbers are kept, times are
+// formatted as RFC3339Nano and all other values are converted to strings. Returns nil if e is nil.
+func (e *Error) ToStatusDetail() map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+	details := make([]interface{}, 0, len(e.fields)/2+3)
+	e.walkAllFields(func(prefix, key string, val interface{}) {
+		if key != "stacktrace" {
+			details = append(details, map[string]interface{}{"key": prefix + key, "value": statusValue(val)})
+		}
+	})
+	return map[string]interface{}{
+		"code":    GRPCCode(e),
+		"message": e.Summary(),
+		"details": details,
+	}
+}
+
+// statusValue converts the given field value to a type supported by structpb.NewValue().
+func statusValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *Error:
+		return v.ErrorNoTrace()
+	case error:
+		return v.Error()
+	default:
+		return toString(v)
+	}
+}
@@ -0,0 +1,68 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{io.EOF, 2},
+		{errors.NoTrace("op"), 2},
+		{errors.NoTrace("op", errors.K.IO), 2},
+		{errors.NoTrace("op", errors.K.Invalid), 3},
+		{errors.NoTrace("op", errors.K.NotExist), 5},
+		{errors.NoTrace("op", errors.K.Permission), 7},
+		{errors.NoTrace("op", errors.K.Unavailable), 14},
+		{context.DeadlineExceeded, 4},
+		{context.Canceled, 1},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, errors.GRPCCode(test.err), test.err)
+	}
+}
+
+func TestError_ToStatusDetail(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := errors.NoTrace("get user", errors.K.NotExist, "user", "u1", "attempts", 3, "since", ts,
+		errors.NoTrace("read", io.EOF, "timeout", time.Second))
+
+	require.Equal(t, map[string]interface{}{
+		"code":    5,
+		"message": "get user failed: item does not exist",
+		"details": []interface{}{
+			map[string]interface{}{"key": "op", "value": "get user"},
+			map[string]interface{}{"key": "kind", "value": "item does not exist"},
+			map[string]interface{}{"key": "user", "value": "u1"},
+			map[string]interface{}{"key": "attempts", "value": 3},
+			map[string]interface{}{"key": "since", "value": "2021-01-02T03:04:05Z"},
+			map[string]interface{}{"key": "cause.op", "value": "read"},
+			map[string]interface{}{"key": "cause.kind", "value": "unclassified error"},
+			map[string]interface{}{"key": "cause.timeout", "value": "1s"},
+			map[string]interface{}{"key": "cause.cause", "value": "EOF"},
+		},
+	}, err.ToStatusDetail())
+
+	// the keys are the same as in AllFields()
+	var keys []string
+	for _, detail := range err.ToStatusDetail()["details"].([]interface{}) {
+		keys = append(keys, detail.(map[string]interface{})["key"].(string))
+	}
+	allKeys := make([]string, 0, len(keys))
+	for key := range err.AllFields() {
+		allKeys = append(allKeys, key)
+	}
+	require.ElementsMatch(t, allKeys, keys)
+
+	require.Nil(t, (*errors.Error)(nil).ToStatusDetail())
+}
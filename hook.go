@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"reflect"
+	"runtime"
+	"sync/atomic"
+)
+
+// OnCreate is an optional callback that is invoked whenever a new error is created with E(), NoTrace(), New() or
+// NewNoTrace() (and functions based on them like Template()). It is not invoked for functions that return an existing
+// *Error, e.g. when Wrap() is called with an *Error.
+//
+// The callback is intended for centralized instrumentation, e.g. counting errors by kind. It runs synchronously on the
+// goroutine that creates the error and must therefore not block. OnCreate should be set once during program
+// initialization and not be modified afterwards.
+var OnCreate func(e *Error)
+
+// OnCreateTransform is an optional function that is invoked whenever a new error is created - see OnCreate for the
+// list of constructors. It allows to decorate all errors uniformly, e.g. with the build version, region or hostname:
+//
+//	errors.OnCreateTransform = func(e *errors.Error) *errors.Error {
+//		return e.With("region", region)
+//	}
+//
+// The transform is invoked before OnCreate. It usually modifies and returns the given error, but may also return a
+// different error, which then replaces the created error. A nil return value is ignored.
+//
+// Errors created by the transform function itself - directly or in any function it calls - are not transformed again.
+// The transform can also be skipped for a specific error by passing SkipTransform to the constructor:
+//
+//	errors.E("op", errors.K.Invalid, errors.SkipTransform)
+//
+// Like OnCreate, the transform should be set once during program initialization and not be modified afterwards.
+var OnCreateTransform func(e *Error) *Error
+
+// SkipTransform is a special argument for E(), NoTrace() and With() that prevents OnCreateTransform from being applied
+// to the created error.
+var SkipTransform = skipTransform{}
+
+type skipTransform struct{}
+
+// created invokes the creation hooks and the KindCounter (if any) on the given newly created error and returns the
+// resulting error.
+func created(e *Error) *Error {
+	if OnCreateTransform != nil && !e.skipTransform && !inCreateTransform() {
+		if t := applyCreateTransform(e); t != nil {
+			e = t
+		}
+	}
+	countKind(e)
+	if OnCreate != nil {
+		OnCreate(e)
+	}
+	return e
+}
+
+// activeTransforms is the number of OnCreateTransform invocations currently in progress on all goroutines.
+var activeTransforms atomic.Int32
+
+//go:noinline
+func applyCreateTransform(e *Error) *Error {
+	activeTransforms.Add(1)
+	defer activeTransforms.Add(-1)
+	return OnCreateTransform(e)
+}
+
+// createTransformEntry is the entry PC of the applyCreateTransform function.
+var createTransformEntry = reflect.ValueOf(applyCreateTransform).Pointer()
+
+// inCreateTransform reports whether the calling goroutine is currently executing OnCreateTransform, i.e. whether the
+// error being created is created by the transform function itself. The call stack is only inspected if a transform is
+// in progress on any goroutine, and then in its entirety, regardless of how deep the error is created within the
+// transform.
+func inCreateTransform() bool {
+	if activeTransforms.Load() == 0 {
+		return false
+	}
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(3, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	for _, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn != nil && fn.Entry() == createTransformEntry {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestOnCreate(t *testing.T) {
+	defer func() { errors.OnCreate = nil }()
+
+	var kinds []errors.Kind
+	errors.OnCreate = func(e *errors.Error) {
+		kinds = append(kinds, e.Kind())
+	}
+
+	err := errors.E("op", errors.K.IO)
+	require.Equal(t, []errors.Kind{errors.K.IO}, kinds)
+
+	_ = errors.NoTrace("op", errors.K.Invalid)
+	_ = errors.New(errors.WithKindOpt(errors.K.NotExist))
+	_ = errors.NewNoTrace(errors.WithKindOpt(errors.K.Timeout))
+	_ = errors.Template("op", errors.K.Permission)()
+	require.Equal(t, []errors.Kind{errors.K.IO, errors.K.Invalid, errors.K.NotExist, errors.K.Timeout, errors.K.Permission}, kinds)
+
+	// no new error is created when wrapping an *Error
+	kinds = nil
+	_ = errors.Wrap(err, "key", "val")
+	require.Empty(t, kinds)
+
+	_ = errors.Wrap(io.EOF)
+	require.Equal(t, []errors.Kind{errors.K.Other}, kinds)
+}
+
+func TestOnCreateTransform(t *testing.T) {
+	defer func() {
+		errors.OnCreateTransform = nil
+		errors.OnCreate = nil
+	}()
+
+	calls := 0
+	errors.OnCreateTransform = func(e *errors.Error) *errors.Error {
+		calls++
+		// errors created within the transform are not transformed again
+		_ = errors.E("nested", errors.K.Internal)
+		return e.With("region", "eu-west")
+	}
+
+	var seen []string
+	errors.OnCreate = func(e *errors.Error) {
+		region, _ := e.GetField("region")
+		seen = append(seen, e.Op()+":"+region)
+	}
+
+	err := errors.E("op", errors.K.IO, "key", "val")
+	require.Equal(t, "op [op] kind [I/O error] key [val] region [eu-west]", err.Error())
+	require.Equal(t, 1, calls)
+	require.Equal(t, []string{"nested:", "op:eu-west"}, seen)
+
+	err = errors.NoTrace("op", errors.SkipTransform)
+	require.Equal(t, "op [op] kind [unclassified error]", err.Error())
+	require.Equal(t, 1, calls)
+
+	// a nil return value is ignored
+	errors.OnCreateTransform = func(e *errors.Error) *errors.Error {
+		return nil
+	}
+	require.Equal(t, "op [op] kind [unclassified error]", errors.E("op").Error())
+
+	// the transform may replace the error
+	errors.OnCreateTransform = func(e *errors.Error) *errors.Error {
+		return errors.NoTrace("replaced", e)
+	}
+	require.Equal(t, "op [replaced] kind [unclassified error] cause:\n\top [op] kind [unclassified error]", errors.E("op").Error())
+}
+
+func TestOnCreateTransformDeep(t *testing.T) {
+	defer func() { errors.OnCreateTransform = nil }()
+
+	// create the error far below the transform in the call stack
+	var deep func(depth int) *errors.Error
+	deep = func(depth int) *errors.Error {
+		if depth == 0 {
+			return errors.E("deep")
+		}
+		return deep(depth - 1)
+	}
+
+	calls := 0
+	errors.OnCreateTransform = func(e *errors.Error) *errors.Error {
+		calls++
+		return e.With("deep", deep(100).Op())
+	}
+
+	err := errors.NoTrace("op")
+	require.Equal(t, 1, calls)
+	require.Equal(t, "op [op] kind [unclassified error] deep [deep]", err.Error())
+}
+
+func TestOnCreateTransformConcurrent(t *testing.T) {
+	defer func() { errors.OnCreateTransform = nil }()
+
+	entered := make(chan bool)
+	release := make(chan bool)
+	errors.OnCreateTransform = func(e *errors.Error) *errors.Error {
+		if e.Op() == "slow" {
+			entered <- true
+			<-release
+		}
+		return e.With("transformed", true)
+	}
+
+	done := make(chan *errors.Error)
+	go func() {
+		done <- errors.NoTrace("slow")
+	}()
+	<-entered
+
+	// errors created on other goroutines while a transform is in progress are still transformed
+	require.Equal(t, "op [other] kind [unclassified error] transformed [true]", errors.NoTrace("other").Error())
+
+	close(release)
+	require.Equal(t, "op [slow] kind [unclassified error] transformed [true]", (<-done).Error())
+}
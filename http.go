@@ -0,0 +1,68 @@
+package errors
+
+// httpStatus maps kinds to HTTP status codes. Kinds that are not listed map to 500 (Internal Server Error).
+var httpStatus = map[Kind]int{
+	K.NotImplemented: 501,
+	K.Invalid:        400,
+	K.Permission:     403,
+	K.Exist:          409,
+	K.NotExist:       404,
+	K.NotFound:       404,
+	K.Finalized:      409,
+	K.NotFinalized:   409,
+	K.NoNetRoute:     502,
+	K.NoMediaMatch:   406,
+	K.Unavailable:    503,
+	K.Cancelled:      499,
+	K.Timeout:        504,
+}
+
+// httpStatusText holds the reason phrases of the status codes in httpStatus. It is embedded here in order to avoid a
+// dependency on net/http.
+var httpStatusText = map[int]string{
+	200: "OK",
+	400: "Bad Request",
+	403: "Forbidden",
+	404: "Not Found",
+	406: "Not Acceptable",
+	409: "Conflict",
+	499: "Client Closed Request", // non-standard, not known to http.StatusText()
+	500: "Internal Server Error",
+	501: "Not Implemented",
+	502: "Bad Gateway",
+	503: "Service Unavailable",
+	504: "Gateway Timeout",
+}
+
+// HTTPStatus returns the HTTP status code corresponding to the kind of the given error as determined by Classify():
+//
+//	K.Invalid                            --> 400 Bad Request
+//	K.Permission                         --> 403 Forbidden
+//	K.NotExist, K.NotFound               --> 404 Not Found
+//	K.NoMediaMatch                       --> 406 Not Acceptable
+//	K.Exist, K.Finalized, K.NotFinalized --> 409 Conflict
+//	K.Cancelled                          --> 499 Client Closed Request (non-standard)
+//	K.NotImplemented                     --> 501 Not Implemented
+//	K.NoNetRoute                         --> 502 Bad Gateway
+//	K.Unavailable                        --> 503 Service Unavailable
+//	K.Timeout                            --> 504 Gateway Timeout
+//	any other kind                       --> 500 Internal Server Error
+//
+// Returns 200 if err is nil, including a nil *Error or *ErrorList.
+func HTTPStatus(err error) int {
+	if isNil(err) {
+		return 200
+	}
+	if status, ok := httpStatus[Classify(err)]; ok {
+		return status
+	}
+	return 500
+}
+
+// HTTPStatusText returns the reason phrase of the status code returned by HTTPStatus(err), e.g. "Not Found". The reason
+// phrases are the same as those returned by http.StatusText(), with the exception of the non-standard status 499 used
+// for K.Cancelled, which returns "Client Closed Request" (http.StatusText(499) returns ""). Returns "OK" if err is nil,
+// including a nil *Error or *ErrorList.
+func HTTPStatusText(err error) string {
+	return httpStatusText[HTTPStatus(err)]
+}
@@ -0,0 +1,49 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantText   string
+	}{
+		{nil, 200, "OK"},
+		{(*errors.Error)(nil), 200, "OK"},
+		{(*errors.ErrorList)(nil), 200, "OK"},
+		{io.EOF, 500, "Internal Server Error"},
+		{errors.NoTrace("op"), 500, "Internal Server Error"},
+		{errors.NoTrace("op", errors.K.Internal), 500, "Internal Server Error"},
+		{errors.NoTrace("op", errors.K.Invalid), 400, "Bad Request"},
+		{errors.NoTrace("op", errors.K.Permission), 403, "Forbidden"},
+		{errors.NoTrace("op", errors.K.NotExist), 404, "Not Found"},
+		{errors.NoTrace("op", errors.K.NotFound), 404, "Not Found"},
+		{errors.NoTrace("op", errors.K.NoMediaMatch), 406, "Not Acceptable"},
+		{errors.NoTrace("op", errors.K.Exist), 409, "Conflict"},
+		{errors.NoTrace("op", errors.K.Cancelled), 499, "Client Closed Request"},
+		{errors.NoTrace("op", errors.K.NotImplemented), 501, "Not Implemented"},
+		{errors.NoTrace("op", errors.K.NoNetRoute), 502, "Bad Gateway"},
+		{errors.NoTrace("op", errors.K.Unavailable), 503, "Service Unavailable"},
+		{errors.NoTrace("op", errors.K.Timeout), 504, "Gateway Timeout"},
+		{errors.NoTrace("op", errors.NoTrace("nested", errors.K.NotExist)), 404, "Not Found"},
+		{context.DeadlineExceeded, 504, "Gateway Timeout"},
+	}
+	for _, test := range tests {
+		t.Run(test.wantText, func(t *testing.T) {
+			require.Equal(t, test.wantStatus, errors.HTTPStatus(test.err))
+			require.Equal(t, test.wantText, errors.HTTPStatusText(test.err))
+			if test.wantStatus != 499 {
+				require.Equal(t, http.StatusText(test.wantStatus), errors.HTTPStatusText(test.err))
+			}
+		})
+	}
+}
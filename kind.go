@@ -1,5 +1,7 @@
 package errors
 
+import "sync"
+
 // Kind is the Go type for error kinds. Use the pre-defined kinds in errors.K, or
 type Kind string
 
@@ -62,3 +64,146 @@ func (k Kind) Default() DefaultKind {
 // the template definition didn't use Default(), the returned error would always be K.Invalid, regardless of the kind
 // in the nested error.
 type DefaultKind string
+
+// kindTranslations holds the registered translations of kind descriptions, keyed by language and kind.
+var kindTranslations = struct {
+	mutex sync.RWMutex
+	texts map[string]map[Kind]string
+}{
+	texts: map[string]map[Kind]string{},
+}
+
+// RegisterKindTranslation registers the description of the given kind in the given language, e.g.
+//
+//	errors.RegisterKindTranslation("de", errors.K.NotExist, "Element existiert nicht")
+//
+// Translations are usually registered during program initialization, but it is safe to register them concurrently
+// with calls to Kind.Describe().
+func RegisterKindTranslation(lang string, k Kind, text string) {
+	kindTranslations.mutex.Lock()
+	defer kindTranslations.mutex.Unlock()
+
+	texts, ok := kindTranslations.texts[lang]
+	if !ok {
+		texts = map[Kind]string{}
+		kindTranslations.texts[lang] = texts
+	}
+	texts[k] = text
+}
+
+// Describe returns the description of this kind in the given language as registered with RegisterKindTranslation. Falls
+// back to the default English description if no translation is registered.
+func (k Kind) Describe(lang string) string {
+	kindTranslations.mutex.RLock()
+	defer kindTranslations.mutex.RUnlock()
+
+	if text, ok := kindTranslations.texts[lang][k]; ok {
+		return text
+	}
+	return string(k)
+}
+
+// kindCodes maps the pre-defined kinds to stable numeric codes for interoperability with systems that identify error
+// kinds by number. K.NotDir has no code since its value is the empty string.
+var kindCodes = map[Kind]int{
+	K.Other:          1,
+	K.NotImplemented: 2,
+	K.Invalid:        3,
+	K.Permission:     4,
+	K.IO:             5,
+	K.Exist:          6,
+	K.NotExist:       7,
+	K.NotFound:       8,
+	K.Finalized:      9,
+	K.NotFinalized:   10,
+	K.NoNetRoute:     11,
+	K.Internal:       12,
+	K.AVProcessing:   13,
+	K.AVInput:        14,
+	K.NoMediaMatch:   15,
+	K.Unavailable:    16,
+	K.Cancelled:      17,
+	K.Timeout:        18,
+	K.Warn:           19,
+}
+
+// registeredKindCodes holds the codes of custom kinds registered with RegisterKindCode.
+var registeredKindCodes = struct {
+	mutex sync.RWMutex
+	codes map[Kind]int
+	kinds map[int]Kind // the first kind registered for a code that is not pre-defined
+}{
+	codes: map[Kind]int{},
+	kinds: map[int]Kind{},
+}
+
+// RegisterKindCode registers the given numeric code for the given custom kind. If the code is already used by a
+// pre-defined or a previously registered kind, the kind becomes an alias of that kind and is considered equal to it by
+// KindEqual(), and hence by IsKind() and Match():
+//
+//	errors.RegisterKindCode("storage: item missing", errors.K.NotExist.Code())
+//	errors.IsKind(errors.K.NotExist, errors.E(errors.Kind("storage: item missing"))) --> true
+//
+// Custom kinds that don't alias a pre-defined kind should use codes of 1000 and above in order to avoid conflicts with
+// future pre-defined kinds. Returns an error if the code is not positive or if the kind is a pre-defined kind.
+//
+// Codes are usually registered during program initialization, but it is safe to register them concurrently with
+// calls to Kind.Code() or KindEqual().
+func RegisterKindCode(k Kind, code int) error {
+	if code <= 0 {
+		return NoTrace("RegisterKindCode", K.Invalid, "reason", "code must be positive", "kind", k, "code", code)
+	}
+	if _, ok := kindCodes[k]; ok || k == "" {
+		return NoTrace("RegisterKindCode", K.Invalid, "reason", "pre-defined kind", "kind", k, "code", code)
+	}
+
+	registeredKindCodes.mutex.Lock()
+	defer registeredKindCodes.mutex.Unlock()
+
+	registeredKindCodes.codes[k] = code
+	if _, ok := registeredKindCodes.kinds[code]; !ok {
+		registeredKindCodes.kinds[code] = k
+	}
+	return nil
+}
+
+// Code returns the numeric code of this kind, or 0 if the kind is neither one of the pre-defined kinds in K nor
+// registered with RegisterKindCode(). The codes of the pre-defined kinds are stable and may be used to exchange kinds
+// with non-Go systems. See KindFromCode().
+func (k Kind) Code() int {
+	if code, ok := kindCodes[k]; ok {
+		return code
+	}
+	registeredKindCodes.mutex.RLock()
+	defer registeredKindCodes.mutex.RUnlock()
+	return registeredKindCodes.codes[k]
+}
+
+// KindFromCode returns the kind with the given numeric code: the pre-defined kind if there is one, or the first kind
+// registered for the code with RegisterKindCode() otherwise. Returns K.Other and false if the code is unknown. See
+// Kind.Code().
+func KindFromCode(code int) (Kind, bool) {
+	for k, c := range kindCodes {
+		if c == code {
+			return k, true
+		}
+	}
+	registeredKindCodes.mutex.RLock()
+	defer registeredKindCodes.mutex.RUnlock()
+	if k, ok := registeredKindCodes.kinds[code]; ok {
+		return k, true
+	}
+	return K.Other, false
+}
+
+// KindEqual returns true if the given kinds represent the same kind: kinds are compared by their numeric code (see
+// Kind.Code()) if both have one, and by their string value otherwise. Hence a kind registered as alias of another kind
+// with RegisterKindCode() is equal to that kind. It is used by IsKind() and Match() and should be preferred over
+// comparing kinds with == in order to respect aliases.
+func KindEqual(a, b Kind) bool {
+	if a == b {
+		return true
+	}
+	ca, cb := a.Code(), b.Code()
+	return ca != 0 && ca == cb
+}
@@ -0,0 +1,103 @@
+package errors_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestKind_Describe(t *testing.T) {
+	errors.RegisterKindTranslation("de", errors.K.NotExist, "Element existiert nicht")
+	errors.RegisterKindTranslation("fr", errors.K.NotExist, "l'élément n'existe pas")
+
+	require.Equal(t, "Element existiert nicht", errors.K.NotExist.Describe("de"))
+	require.Equal(t, "l'élément n'existe pas", errors.K.NotExist.Describe("fr"))
+	require.Equal(t, "item does not exist", errors.K.NotExist.Describe(""))
+	require.Equal(t, "item does not exist", errors.K.NotExist.Describe("it"))
+	require.Equal(t, "permission denied", errors.K.Permission.Describe("de"))
+
+	errors.RegisterKindTranslation("de", errors.K.NotExist, "Objekt existiert nicht")
+	require.Equal(t, "Objekt existiert nicht", errors.K.NotExist.Describe("de"))
+}
+
+func TestKind_Describe_concurrent(t *testing.T) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		lang := fmt.Sprint("lang", i)
+		go func() {
+			defer wg.Done()
+			errors.RegisterKindTranslation(lang, errors.K.IO, "io "+lang)
+		}()
+		go func() {
+			defer wg.Done()
+			desc := errors.K.IO.Describe(lang)
+			require.Contains(t, []string{"I/O error", "io " + lang}, desc)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestKind_Code(t *testing.T) {
+	require.Equal(t, 1, errors.K.Other.Code())
+	require.Equal(t, 7, errors.K.NotExist.Code())
+	require.Equal(t, 0, errors.Kind("bogus").Code())
+	require.Equal(t, 0, errors.K.NotDir.Code())
+
+	k, ok := errors.KindFromCode(7)
+	require.True(t, ok)
+	require.Equal(t, errors.K.NotExist, k)
+
+	k, ok = errors.KindFromCode(0)
+	require.False(t, ok)
+	require.Equal(t, errors.K.Other, k)
+
+	// codes are unique
+	kinds := map[int]errors.Kind{}
+	for code := 1; code < 100; code++ {
+		if k, ok := errors.KindFromCode(code); ok {
+			require.Equal(t, code, k.Code())
+			kinds[code] = k
+		}
+	}
+	require.Len(t, kinds, 19)
+}
+
+func TestKindEqual(t *testing.T) {
+	require.True(t, errors.KindEqual(errors.K.IO, errors.K.IO))
+	require.True(t, errors.KindEqual(errors.K.IO, errors.Kind("I/O error")))
+	require.True(t, errors.KindEqual("custom", "custom"))
+	require.False(t, errors.KindEqual(errors.K.IO, errors.K.Invalid))
+	require.False(t, errors.KindEqual("custom", "other custom"))
+}
+
+func TestRegisterKindCode(t *testing.T) {
+	require.NoError(t, errors.RegisterKindCode("storage: item missing", errors.K.NotExist.Code()))
+	require.NoError(t, errors.RegisterKindCode("a: conflict", 1001))
+	require.NoError(t, errors.RegisterKindCode("b: conflict", 1001))
+
+	require.Error(t, errors.RegisterKindCode(errors.K.IO, 1002))
+	require.Error(t, errors.RegisterKindCode("custom", 0))
+
+	require.Equal(t, errors.K.NotExist.Code(), errors.Kind("storage: item missing").Code())
+	require.True(t, errors.KindEqual("storage: item missing", errors.K.NotExist))
+	require.True(t, errors.KindEqual("a: conflict", "b: conflict"))
+	require.False(t, errors.KindEqual("a: conflict", errors.K.Exist))
+	require.False(t, errors.KindEqual("a: conflict", "unregistered conflict"))
+
+	k, ok := errors.KindFromCode(errors.K.NotExist.Code())
+	require.True(t, ok)
+	require.Equal(t, errors.K.NotExist, k)
+	k, ok = errors.KindFromCode(1001)
+	require.True(t, ok)
+	require.Equal(t, errors.Kind("a: conflict"), k)
+
+	err := errors.E("read", errors.Kind("storage: item missing"))
+	require.True(t, errors.IsKind(errors.K.NotExist, err))
+	require.True(t, errors.Match(errors.E(errors.K.NotExist), err))
+	require.True(t, errors.IsKind(errors.Kind("b: conflict"), errors.E("write", errors.Kind("a: conflict"))))
+}
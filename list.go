@@ -1,7 +1,11 @@
 package errors
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -11,31 +15,28 @@ import (
 // If err is not an *ErrorList (any other type of error or nil), then a new list is created and err added to it. Then
 // all additional errs are appended, unwrapping them if any of them are ErrorLists themselves.
 //
-// Any nil errors within errs will be ignored. If the final list contains a single error, the error is returned instead
-// of the list.
+// Any nil errors within errs will be ignored, including nil *Error and *ErrorList pointers. If the final list contains a
+// single error, the error is returned instead of the list. Append never returns a nil pointer wrapped in a non-nil error
+// interface.
 //
 //
 func Append(err error, errs ...error) error {
 	for _, e := range errs {
-		if e != nil {
+		if !isNil(e) {
 			break
 		}
 		errs = errs[1:]
 	}
+	if isNil(err) {
+		// prevent returning a nil *Error or *ErrorList as non-nil error interface
+		err = nil
+	}
 	if len(errs) == 0 {
 		return err
 	}
 
 	list, ok := err.(*ErrorList)
-	if ok {
-		//  may be a nil interface
-		if list == nil {
-			if len(errs) == 1 {
-				return errs[0]
-			}
-			list = new(ErrorList)
-		}
-	} else {
+	if !ok {
 		list = new(ErrorList)
 		list.Append(err)
 	}
@@ -43,9 +44,19 @@ func Append(err error, errs ...error) error {
 	return list.ErrorOrNil()
 }
 
+// MarshalErrorListSummary controls whether an ErrorList is marshaled to JSON with a summary of its errors: the number
+// of errors as "count" and the number of errors per kind as "by_kind" (see ErrorList.CountByKind()):
+//
+//	{"count":3,"by_kind":{"I/O error":2,"invalid":1},"errors":[...]}
+//
+// If disabled (the default), only the errors are marshaled.
+var MarshalErrorListSummary = false
+
 // ErrorList is a collection of errors.
 type ErrorList struct {
 	Errors []error
+	// AppendfKind is the kind of the errors created by Appendf(). Defaults to K.Invalid if not set.
+	AppendfKind Kind
 }
 
 func (e *ErrorList) Append(errs ...error) {
@@ -57,7 +68,7 @@ func (e *ErrorList) Append(errs ...error) {
 				e.doAppend(err.Errors...)
 			}
 		default:
-			if err != nil {
+			if !isNil(err) {
 				e.doAppend(err)
 			}
 		}
@@ -104,15 +115,244 @@ func (e *ErrorList) ErrorOrNil() error {
 	return e
 }
 
+// CountByKind returns the number of errors in the list per kind. Errors that are not an *Error are counted as K.Other.
+func (e *ErrorList) CountByKind() map[Kind]int {
+	res := map[Kind]int{}
+	if e == nil {
+		return res
+	}
+	for _, err := range e.Errors {
+		res[elementKind(err)]++
+	}
+	return res
+}
+
+// Partition splits the list into errors and warnings: the returned warnings list contains all errors of kind K.Warn,
+// the errors list all others, including errors that are not an *Error. Both lists retain the order of this list and are
+// never nil:
+//
+//	errs, warnings := list.Partition()
+//	log.Warn("validation warnings", "warnings", warnings)
+//	return errs.ErrorOrNil()
+func (e *ErrorList) Partition() (errs *ErrorList, warnings *ErrorList) {
+	errs, warnings = &ErrorList{}, &ErrorList{}
+	if e == nil {
+		return errs, warnings
+	}
+	for _, err := range e.Errors {
+		if elementKind(err) == K.Warn {
+			warnings.doAppend(err)
+		} else {
+			errs.doAppend(err)
+		}
+	}
+	return errs, warnings
+}
+
+// Appendf appends a new *Error with the message formatted according to the given format specifier. The error's kind is
+// AppendfKind, or K.Invalid if AppendfKind is not set. It is a shortcut for a common pattern in validation code:
+//
+//	list.Appendf("field %s is invalid: %v", name, reason)
+//	// same as
+//	list.Append(errors.E(errors.K.Invalid).WithMessage(fmt.Sprintf("field %s is invalid: %v", name, reason)))
+func (e *ErrorList) Appendf(format string, args ...interface{}) {
+	kind := e.AppendfKind
+	if kind == "" {
+		kind = K.Invalid
+	}
+	e.doAppend(created(newErrorWithStack([]interface{}{kind}).WithMessage(fmt.Sprintf(format, args...))))
+}
+
+// WriteCSV writes the errors of this list as CSV to the given writer: a header row with the given columns followed by
+// one row per error. The columns are field keys and default to "op", "kind" and "cause":
+//
+//	list.WriteCSV(os.Stdout, "op", "kind", "file")
+//	--> op,kind,file
+//	    read,I/O error,a.txt
+//	    parse,invalid,b.txt
+//
+// The values of *Error entries are retrieved with Error.Field(), nested errors are written without stacktrace and
+// missing fields as empty string. For errors that are not an *Error, the "op" and "cause" columns contain the error
+// message. Returns an error of kind K.IO if writing fails.
+func (e *ErrorList) WriteCSV(w io.Writer, columns ...string) error {
+	if len(columns) == 0 {
+		columns = []string{OpKey, KindKey, CauseKey}
+	}
+	cw := csv.NewWriter(w)
+	_ = cw.Write(columns)
+	if e != nil {
+		row := make([]string, len(columns))
+		for _, err := range e.Errors {
+			for i, col := range columns {
+				row[i] = csvValue(err, col)
+			}
+			_ = cw.Write(row)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return E("write csv", K.IO, err)
+	}
+	return nil
+}
+
+// csvValue returns the value of the given column for the given list element.
+func csvValue(err error, col string) string {
+	e, ok := err.(*Error)
+	if !ok {
+		if col == OpKey || col == CauseKey {
+			return err.Error()
+		}
+		return ""
+	}
+	switch val := e.Field(col).(type) {
+	case *Error:
+		return val.ErrorNoTrace()
+	case error:
+		return val.Error()
+	default:
+		return toString(val)
+	}
+}
+
+// elementKind returns the kind of the given list element: the kind of an *Error, K.Other for any other error.
+func elementKind(err error) Kind {
+	if e, ok := err.(*Error); ok {
+		return e.Kind()
+	}
+	return K.Other
+}
+
+// ErrorOrNilExcluding is like ErrorOrNil, but also returns nil if the kinds of all errors in the list are among the
+// given kinds. This allows to ignore lists that contain only warnings, for example:
+//
+//	return list.ErrorOrNilExcluding(errors.K.Warn)
+//
+// If at least one error has a kind that is not excluded, the result is the same as ErrorOrNil(), i.e. the excluded
+// errors are retained. Errors that are not an *Error are considered to be of kind K.Other.
+func (e *ErrorList) ErrorOrNilExcluding(kinds ...Kind) error {
+	if e == nil {
+		return nil
+	}
+	for _, err := range e.Errors {
+		kind := elementKind(err)
+		excluded := false
+		for _, k := range kinds {
+			if kind == k {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			return e.ErrorOrNil()
+		}
+	}
+	return nil
+}
+
+// Is reports whether any error in the list matches the target according to the standard library's errors.Is(). This
+// allows to use errors.Is() on errors whose cause chain includes an ErrorList:
+//
+//	err := errors.E("op", errors.Append(io.EOF, io.ErrClosedPipe))
+//	errors.Is(err, io.EOF)          // true
+//	errors.Is(err, io.ErrClosedPipe) // true
+func (e *ErrorList) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	for _, err := range e.Errors {
+		if stderrors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in the list that matches the target according to the standard library's errors.As(), and
+// if so, sets target to that error value and returns true.
+func (e *ErrorList) As(target interface{}) bool {
+	if e == nil {
+		return false
+	}
+	for _, err := range e.Errors {
+		if stderrors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the single error of a list with exactly one error, and nil otherwise. Use Is() and As() to inspect all
+// errors of the list.
+func (e *ErrorList) Unwrap() error {
+	if e == nil || len(e.Errors) != 1 {
+		return nil
+	}
+	return e.Errors[0]
+}
+
+// MarshalJSON marshals the list as JSON object with an "errors" array. In contrast to Error(), a list with a single
+// error is marshaled as list as well in order to keep the wire format stable. If MarshalErrorListSummary is enabled, the
+// object contains a summary of the errors in addition.
 func (e *ErrorList) MarshalJSON() ([]byte, error) {
+	errs := e.Errors // local copy to prevent concurrency issues
+	if MarshalErrorListSummary {
+		byKind := map[string]int{}
+		for kind, count := range e.CountByKind() {
+			byKind[string(kind)] = count
+		}
+		return json.Marshal(struct {
+			Count  int            `json:"count"`
+			ByKind map[string]int `json:"by_kind"`
+			Errors []interface{}  `json:"errors"`
+		}{
+			Count:  len(errs),
+			ByKind: byKind,
+			Errors: e.errorsForJSON(),
+		})
+	}
 	return json.Marshal(map[string]interface{}{"errors": e.errorsForJSON()})
 }
 
+// UnmarshalJSON unmarshals a list of errors as marshaled by MarshalJSON. A JSON string or a marshaled Error (a JSON
+// object with a "kind" key but no "errors" key) is unmarshaled as a list with a single error. Any other non-empty JSON
+// object results in an error.
 func (e *ErrorList) UnmarshalJSON(bts []byte) error {
 	list := struct {
 		Errors []valOrMap `json:"errors"`
 	}{}
 
+	switch jsonType(bts) {
+	case "string":
+		var s string
+		if err := json.Unmarshal(bts, &s); err != nil {
+			return err
+		}
+		if s != "" {
+			e.Append(Str(s))
+		}
+		return nil
+	case "object":
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(bts, &keys); err != nil {
+			return err
+		}
+		if _, ok := keys["errors"]; !ok {
+			if _, ok = keys[KindKey]; !ok {
+				if len(keys) == 0 {
+					return nil
+				}
+				return E("unmarshal", K.Invalid, "reason", "JSON object has neither errors nor kind")
+			}
+			single := &Error{}
+			if err := single.UnmarshalJSON(bts); err != nil {
+				return err
+			}
+			e.Append(single)
+			return nil
+		}
+	}
+
 	err := json.Unmarshal(bts, &list)
 	if err != nil {
 		return err
@@ -128,6 +368,41 @@ func (e *ErrorList) UnmarshalJSON(bts []byte) error {
 	return nil
 }
 
+// Flatten returns a new, flat list of the leaf errors of this list. Nested ErrorLists are expanded recursively, and so
+// are *Errors whose cause chain ends in an ErrorList: such errors are replaced by the (flattened) errors of that list.
+// All other errors are added to the new list as is. The original list is not modified.
+//
+//	list: [ E("op1", Append(io.EOF, E("op2"))), io.ErrClosedPipe ]
+//	flat: [ io.EOF, E("op2"), io.ErrClosedPipe ]
+//
+// Returns nil if e is nil.
+func (e *ErrorList) Flatten() *ErrorList {
+	if e == nil {
+		return nil
+	}
+	res := &ErrorList{}
+	e.flattenInto(res)
+	return res
+}
+
+func (e *ErrorList) flattenInto(res *ErrorList) {
+	for _, err := range e.Errors {
+		leaf := err
+		for {
+			ee, ok := leaf.(*Error)
+			if !ok || ee == nil || ee.cause == nil {
+				break
+			}
+			leaf = ee.cause
+		}
+		if list, ok := leaf.(*ErrorList); ok && list != nil {
+			list.flattenInto(res)
+		} else {
+			res.doAppend(err)
+		}
+	}
+}
+
 // errorsForJSON returns the list of errors in a form apt for JSON marshalling. Specifically, it replaces errors
 // implementing the standard "error" interface with their string implementation, because otherwise they would be
 // marshalled to nil by json.Marshal().
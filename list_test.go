@@ -1,9 +1,11 @@
 package errors_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,7 +123,8 @@ func TestErrorList_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 
 	err = list.UnmarshalJSON([]byte(`{"blub":"blob"}`))
-	assert.NoError(t, err)
+	assert.Error(t, err)
+	assert.True(t, errors.IsKind(errors.K.Invalid, err))
 
 	err = list.UnmarshalJSON([]byte(`{"errors":["EOF",{"op":"op1","kind":"invalid"}]}`))
 	require.NoError(t, err)
@@ -162,6 +165,199 @@ func TestErrorList_JSON(t *testing.T) {
 	}
 }
 
+func TestErrorList_ErrorOrNilExcluding(t *testing.T) {
+	warn1 := errors.NoTrace("validate", errors.K.Warn, "field", "name")
+	warn2 := errors.NoTrace("validate", errors.K.Warn, "field", "age")
+	invalid := errors.NoTrace("validate", errors.K.Invalid, "field", "id")
+
+	var list *errors.ErrorList
+	require.Nil(t, list.ErrorOrNilExcluding(errors.K.Warn))
+
+	list = &errors.ErrorList{}
+	require.Nil(t, list.ErrorOrNilExcluding(errors.K.Warn))
+
+	list.Append(warn1, warn2)
+	require.Nil(t, list.ErrorOrNilExcluding(errors.K.Warn))
+	require.Equal(t, list, list.ErrorOrNilExcluding())
+	require.Equal(t, list, list.ErrorOrNilExcluding(errors.K.Invalid))
+
+	list.Append(invalid)
+	require.Equal(t, list, list.ErrorOrNilExcluding(errors.K.Warn))
+	require.Nil(t, list.ErrorOrNilExcluding(errors.K.Warn, errors.K.Invalid))
+
+	list = &errors.ErrorList{}
+	list.Append(invalid)
+	require.Equal(t, invalid, list.ErrorOrNilExcluding(errors.K.Warn))
+
+	// non-*Error errors are of kind Other
+	list.Append(io.EOF)
+	require.Equal(t, list, list.ErrorOrNilExcluding(errors.K.Invalid))
+	require.Nil(t, list.ErrorOrNilExcluding(errors.K.Invalid, errors.K.Other))
+}
+
+func TestErrorList_CountByKind(t *testing.T) {
+	list := &errors.ErrorList{}
+	require.Equal(t, map[errors.Kind]int{}, list.CountByKind())
+
+	list.Append(
+		errors.NoTrace("op1", errors.K.IO),
+		errors.NoTrace("op2", errors.NoTrace(errors.K.IO)),
+		errors.NoTrace("op3", errors.K.Invalid),
+		io.EOF)
+	require.Equal(t, map[errors.Kind]int{errors.K.IO: 2, errors.K.Invalid: 1, errors.K.Other: 1}, list.CountByKind())
+}
+
+func TestErrorList_Partition(t *testing.T) {
+	var nilList *errors.ErrorList
+	errs, warnings := nilList.Partition()
+	require.Empty(t, errs.Errors)
+	require.Empty(t, warnings.Errors)
+
+	w1 := errors.NoTrace("op1", errors.K.Warn)
+	e1 := errors.NoTrace("op2", errors.K.Invalid)
+	w2 := errors.NoTrace("op3", errors.NoTrace(errors.K.Warn))
+	list := errors.Append(w1, e1, io.EOF, w2).(*errors.ErrorList)
+
+	errs, warnings = list.Partition()
+	require.Equal(t, []error{e1, io.EOF}, errs.Errors)
+	require.Equal(t, []error{w1, w2}, warnings.Errors)
+	require.Len(t, list.Errors, 4)
+}
+
+func TestErrorList_WriteCSV(t *testing.T) {
+	list := errors.Append(
+		errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt"),
+		errors.NoTrace("parse", errors.K.Invalid, errors.NoTrace("decode", "line", 7), "file", "b,c.txt"),
+		io.ErrUnexpectedEOF).(*errors.ErrorList)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, list.WriteCSV(buf))
+	require.Equal(t, `op,kind,cause
+read,I/O error,EOF
+parse,invalid,op [decode] kind [unclassified error] line [7]
+unexpected EOF,,unexpected EOF
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, list.WriteCSV(buf, "op", "file", "line"))
+	require.Equal(t, `op,file,line
+read,a.txt,
+parse,"b,c.txt",7
+unexpected EOF,,
+`, buf.String())
+
+	var nilList *errors.ErrorList
+	buf.Reset()
+	require.NoError(t, nilList.WriteCSV(buf, "op"))
+	require.Equal(t, "op\n", buf.String())
+
+	err := list.WriteCSV(failingWriter{})
+	require.True(t, errors.IsKind(errors.K.IO, err))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestErrorList_Appendf(t *testing.T) {
+	list := &errors.ErrorList{}
+	list.Appendf("field %s is invalid: %v", "name", "too long")
+	require.Len(t, list.Errors, 1)
+	require.True(t, errors.IsKind(errors.K.Invalid, list.Errors[0]))
+	require.Equal(t, "field name is invalid: too long", errors.Message(list.Errors[0]))
+
+	list.AppendfKind = errors.K.Warn
+	list.Appendf("field %s is deprecated", "alias")
+	require.Len(t, list.Errors, 2)
+	require.True(t, errors.IsKind(errors.K.Warn, list.Errors[1]))
+	require.Equal(t, "kind [warning] message [field alias is deprecated]", errors.ClearStacktrace(list.Errors[1]).Error())
+}
+
+func TestErrorList_Appendf_OnCreate(t *testing.T) {
+	defer func() { errors.OnCreate = nil }()
+
+	var messages []string
+	errors.OnCreate = func(e *errors.Error) {
+		messages = append(messages, errors.Message(e))
+	}
+
+	list := &errors.ErrorList{}
+	list.Appendf("field %s is invalid", "name")
+	require.Equal(t, []string{"field name is invalid"}, messages)
+}
+
+func TestErrorList_Appendf_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	list := &errors.ErrorList{}
+	list.Appendf("field %s is invalid", "name")
+	s := list.Error()
+	require.NotContains(t, s, "Appendf()")
+	require.Contains(t, s, "TestErrorList_Appendf_Stacktrace()")
+}
+
+func TestErrorList_MarshalJSON_summary(t *testing.T) {
+	defer func() { errors.MarshalErrorListSummary = false }()
+	errors.MarshalErrorListSummary = true
+
+	list := &errors.ErrorList{}
+	list.Append(
+		errors.NoTrace("op1", errors.K.IO),
+		errors.NoTrace("op2", errors.K.IO),
+		errors.NoTrace("op3", errors.K.Invalid))
+
+	jsn, err := json.Marshal(list)
+	require.NoError(t, err)
+	require.Equal(t, `{"count":3,"by_kind":{"I/O error":2,"invalid":1},"errors":[`+
+		`{"op":"op1","kind":"I/O error"},{"op":"op2","kind":"I/O error"},{"op":"op3","kind":"invalid"}]}`, string(jsn))
+
+	var unmarshalled errors.ErrorList
+	require.NoError(t, json.Unmarshal(jsn, &unmarshalled))
+	require.Equal(t, list.Error(), unmarshalled.Error())
+
+	// single errors are marshaled with summary, too
+	single := &errors.ErrorList{}
+	single.Append(errors.NoTrace("op1", errors.K.IO))
+	jsn, err = json.Marshal(single)
+	require.NoError(t, err)
+	require.Equal(t, `{"count":1,"by_kind":{"I/O error":1},"errors":[{"op":"op1","kind":"I/O error"}]}`, string(jsn))
+}
+
+func TestErrorList_Is(t *testing.T) {
+	err := errors.E("op", errors.Append(io.EOF, io.ErrClosedPipe))
+	require.True(t, errors.Is(err, io.EOF))
+	require.True(t, errors.Is(err, io.ErrClosedPipe))
+	require.False(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	// nested *Errors within the list
+	err = errors.E("op", errors.Append(errors.E("read", io.EOF), errors.E("write", errors.E(io.ErrClosedPipe))))
+	require.True(t, errors.Is(err, io.EOF))
+	require.True(t, errors.Is(err, io.ErrClosedPipe))
+	require.False(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	var list *errors.ErrorList
+	require.False(t, list.Is(io.EOF))
+}
+
+func TestErrorList_As(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/tmp/a.txt", Err: fs.ErrNotExist}
+	err := errors.E("op", errors.Append(io.EOF, errors.E("read", pathErr)))
+
+	var target *fs.PathError
+	require.True(t, errors.As(err, &target))
+	require.Equal(t, pathErr, target)
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+
+	err = errors.E("op", errors.Append(io.EOF, io.ErrClosedPipe))
+	require.False(t, errors.As(err, &target))
+
+	var list *errors.ErrorList
+	require.False(t, list.As(&target))
+}
+
 func ExampleAppend() {
 	{
 		fmt.Println("nil error:")
@@ -244,3 +440,105 @@ func assertErrorList(t *testing.T, list error, expected ...error) {
 		require.Fail(t, "not an error list", list)
 	}
 }
+
+func TestErrorList_Flatten(t *testing.T) {
+	var nilList *errors.ErrorList
+	require.Nil(t, nilList.Flatten())
+	require.Empty(t, (&errors.ErrorList{}).Flatten().Errors)
+
+	op2 := errors.NoTrace("op2")
+	op4 := errors.NoTrace("op4", io.ErrShortWrite)
+	inner := &errors.ErrorList{Errors: []error{io.ErrUnexpectedEOF, op4}}
+	list := &errors.ErrorList{Errors: []error{
+		errors.NoTrace("op1", errors.Append(io.EOF, op2)),
+		io.ErrClosedPipe,
+		errors.NoTrace("op3", errors.NoTrace("nested", inner)),
+		&errors.ErrorList{Errors: []error{io.ErrNoProgress}},
+	}}
+	before := list.Error()
+
+	flat := list.Flatten()
+	require.Equal(t, []error{io.EOF, op2, io.ErrClosedPipe, io.ErrUnexpectedEOF, op4, io.ErrNoProgress}, flat.Errors)
+	require.NotSame(t, list, flat)
+	require.Equal(t, before, list.Error())
+
+	// nil errors in a list that was not built with Append
+	nilErr := (*errors.Error)(nil)
+	require.Equal(t, []error{nilErr, io.EOF}, (&errors.ErrorList{Errors: []error{nilErr, io.EOF}}).Flatten().Errors)
+}
+
+func TestErrorList_single(t *testing.T) {
+	e := errors.NoTrace("read", errors.K.IO, io.EOF)
+	list := &errors.ErrorList{Errors: []error{e}}
+
+	require.Equal(t, e.Error(), list.Error())
+	require.Same(t, e, list.Unwrap())
+	require.True(t, errors.Is(list, io.EOF))
+
+	// a single error is marshaled in the list envelope
+	bare, err := json.Marshal(e)
+	require.NoError(t, err)
+	got, err := json.Marshal(list)
+	require.NoError(t, err)
+	require.Equal(t, `{"errors":[`+string(bare)+`]}`, string(got))
+
+	got, err = json.Marshal(&errors.ErrorList{Errors: []error{io.EOF}})
+	require.NoError(t, err)
+	require.Equal(t, `{"errors":["EOF"]}`, string(got))
+
+	// both the envelope and a bare error are unmarshaled
+	for _, jsn := range [][]byte{got, bare} {
+		var unmarshalled errors.ErrorList
+		require.NoError(t, json.Unmarshal(jsn, &unmarshalled))
+		require.Len(t, unmarshalled.Errors, 1)
+	}
+	var unmarshalled errors.ErrorList
+	require.NoError(t, json.Unmarshal(bare, &unmarshalled))
+	require.Equal(t, e.Error(), unmarshalled.Error())
+
+	require.Nil(t, (&errors.ErrorList{}).Unwrap())
+	require.Nil(t, (&errors.ErrorList{Errors: []error{io.EOF, e}}).Unwrap())
+}
+
+/*
+	$ go test -bench "^BenchmarkErrorList_Single" -run "^Benchmark" github.com/eluv-io/errors-go
+	goos: linux
+	goarch: amd64
+	pkg: github.com/eluv-io/errors-go
+	BenchmarkErrorList_Single/Error/bare         	 1320901	       890.3 ns/op	     282 B/op	      12 allocs/op
+	BenchmarkErrorList_Single/Error/list         	 1371039	       944.1 ns/op	     282 B/op	      12 allocs/op
+	BenchmarkErrorList_Single/MarshalJSON/bare   	  570285	      2407 ns/op	     424 B/op	      24 allocs/op
+	BenchmarkErrorList_Single/MarshalJSON/list   	  239952	      4685 ns/op	     968 B/op	      34 allocs/op
+
+	MarshalJSON of the list includes the "errors" envelope.
+*/
+
+func BenchmarkErrorList_Single(b *testing.B) {
+	e := errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt")
+	list := &errors.ErrorList{Errors: []error{e}}
+
+	b.Run("Error/bare", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = e.Error()
+		}
+	})
+	b.Run("Error/list", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = list.Error()
+		}
+	})
+	b.Run("MarshalJSON/bare", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal(e)
+		}
+	})
+	b.Run("MarshalJSON/list", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal(list)
+		}
+	})
+}
@@ -9,3 +9,17 @@ type nilError struct{}
 func (n *nilError) Error() string {
 	return ""
 }
+
+// isNil returns true if err is nil or a nil *Error or *ErrorList. The latter are not nil when assigned to an error
+// interface, but do not represent an error either.
+func isNil(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return true
+	case *Error:
+		return e == nil
+	case *ErrorList:
+		return e == nil
+	}
+	return false
+}
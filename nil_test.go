@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,3 +17,40 @@ func TestNilError(t *testing.T) {
 func nilErr() error {
 	return NilError
 }
+
+func TestIsNil(t *testing.T) {
+	require.True(t, isNil(nil))
+	require.True(t, isNil((*Error)(nil)))
+	require.True(t, isNil((*ErrorList)(nil)))
+	require.False(t, isNil(NilError))
+	require.False(t, isNil(E("op")))
+	require.False(t, isNil(&ErrorList{}))
+}
+
+func TestTypedNil(t *testing.T) {
+	var nilE *Error
+	var nilList *ErrorList
+
+	// all results are checked against nil at the interface level
+	require.True(t, Return(nilE) == nil)
+	require.True(t, Return(E()) == nil)
+	require.True(t, Return(Wrap(nil, "op", "read")) == nil)
+	require.True(t, Return(FromContext(context.Background(), "op")) == nil)
+
+	require.True(t, Append(nilE) == nil)
+	require.True(t, Append(nilList) == nil)
+	require.True(t, Append(nil, nilE, nilList) == nil)
+	require.True(t, Append(nilE, nilE) == nil)
+	require.True(t, Append(nilList, nilE) == nil)
+	require.Equal(t, io.EOF, Append(nilE, nilE, io.EOF))
+
+	list := &ErrorList{}
+	list.Append(nilE, nilList)
+	require.True(t, list.ErrorOrNil() == nil)
+
+	require.True(t, ClearStacktrace(nilE) == nil)
+	require.True(t, T("op").IfNotNil(nilE) == nil)
+	require.True(t, E("op").WithCause(nilE).Unwrap() == nil)
+	require.True(t, E("op", nilE).Cause() == nil)
+	require.Equal(t, NilError, GetRootCause(nilE))
+}
@@ -2,14 +2,22 @@
 
 package errors
 
-import "bytes"
+import (
+	"bytes"
+
+	gostack "github.com/eluv-io/stack"
+)
 
 // stack is a noop implementation that disables stack collection & printing when the errnostack build tag is set. See
 // stack.go for futher information.
 type stack struct{}
 
 func (e *Error) populateStack()               {}
+func (e *Error) populatePanicStack()          {}
 func (e *Error) printStack(*bytes.Buffer)     {}
 func (e *Error) dropStackFrames(n int) *Error { return e }
 func (e *Error) hasStack() bool               { return false }
 func (e *Error) clearStack()                  {}
+
+// CallStack returns nil since stacktraces are disabled with the "errnostack" build tag.
+func (e *Error) CallStack() gostack.CallStack { return nil }
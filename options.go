@@ -0,0 +1,65 @@
+package errors
+
+// Option is a functional option that initializes an Error created with New().
+type Option func(e *Error)
+
+// WithOp returns an option that sets the error's operation. See Error.WithOp().
+func WithOp(op string) Option {
+	return func(e *Error) {
+		_ = e.WithOp(op)
+	}
+}
+
+// WithKindOpt returns an option that sets the error's kind. See Error.WithKind().
+func WithKindOpt(kind Kind) Option {
+	return func(e *Error) {
+		_ = e.WithKind(kind)
+	}
+}
+
+// WithCauseOpt returns an option that sets the error's cause. See Error.WithCause().
+func WithCauseOpt(err error) Option {
+	return func(e *Error) {
+		_ = e.WithCause(err)
+	}
+}
+
+// WithField returns an option that adds the given key-value pair to the error's fields. See Error.With().
+func WithField(key string, val interface{}) Option {
+	return func(e *Error) {
+		_ = e.With(key, val)
+	}
+}
+
+// New creates a new error initialized with the given options. It is an alternative to E() for callers that prefer a
+// self-documenting, order-independent construction style:
+//
+//	errors.New(errors.WithKindOpt(errors.K.NotExist), errors.WithField("file", f))
+//	--> same as errors.E(errors.K.NotExist, "file", f)
+//
+// Like E(), New populates the error's stacktrace according to PopulateStacktrace() and StacktraceSampleRate().
+func New(opts ...Option) *Error {
+	e := applyOptions(opts)
+
+	if PopulateStacktrace() && sampleStacktrace() {
+		e.populateStack()
+	}
+
+	return created(e)
+}
+
+// NewNoTrace is the same as New, but does not populate a stack trace. Use in cases where the stacktrace is not desired.
+func NewNoTrace(opts ...Option) *Error {
+	return created(applyOptions(opts))
+}
+
+// applyOptions creates a new error and applies the given options to it.
+func applyOptions(opts []Option) *Error {
+	e := &Error{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+	return e
+}
@@ -0,0 +1,91 @@
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		err  *errors.Error
+		want string
+	}{
+		{errors.New(), "kind [unclassified error]"},
+		{errors.New(nil), "kind [unclassified error]"},
+		{errors.New(errors.WithOp("operation")), "op [operation] kind [unclassified error]"},
+		{errors.New(errors.WithKindOpt(errors.K.IO)), "kind [I/O error]"},
+		{errors.New(errors.WithCauseOpt(io.EOF)), "kind [unclassified error] cause [EOF]"},
+		{errors.New(errors.WithField("key", "val")), "kind [unclassified error] key [val]"},
+		{
+			errors.New(
+				errors.WithField("key1", "val1"),
+				errors.WithCauseOpt(io.EOF),
+				errors.WithKindOpt(errors.K.IO),
+				errors.WithField("key2", 2),
+				errors.WithOp("operation")),
+			"op [operation] kind [I/O error] key1 [val1] key2 [2] cause [EOF]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, test.err.Error())
+		})
+	}
+}
+
+func TestNew_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	err := errors.New(errors.WithOp("operation"))
+	assert.Contains(t, err.Error(), "TestNew_Stacktrace()")
+}
+
+func TestNewNoTrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	err := errors.NewNoTrace(errors.WithOp("operation"))
+	assert.Equal(t, "op [operation] kind [unclassified error]", err.Error())
+}
+
+func TestNew_EqualsE(t *testing.T) {
+	tests := []struct {
+		opts []errors.Option
+		args []interface{}
+	}{
+		{nil, nil},
+		{
+			[]errors.Option{errors.WithOp("operation")},
+			[]interface{}{"operation"},
+		},
+		{
+			[]errors.Option{errors.WithKindOpt(errors.K.Invalid), errors.WithField("file", "a.txt")},
+			[]interface{}{errors.K.Invalid, "file", "a.txt"},
+		},
+		{
+			[]errors.Option{
+				errors.WithField("k1", "v1"),
+				errors.WithCauseOpt(io.EOF),
+				errors.WithOp("operation"),
+				errors.WithField("k2", 2),
+				errors.WithKindOpt(errors.K.IO),
+			},
+			[]interface{}{"operation", errors.K.IO, io.EOF, "k1", "v1", "k2", 2},
+		},
+	}
+
+	for _, test := range tests {
+		want := errors.E(test.args...)
+		t.Run(want.Error(), func(t *testing.T) {
+			require.True(t, errors.New(test.opts...).Equal(want))
+			require.True(t, errors.NewNoTrace(test.opts...).Equal(errors.NoTrace(test.args...)))
+		})
+	}
+}
@@ -18,7 +18,7 @@ func (a *orderedMap) Append(kvs ...interface{}) {
 		a.Set(toString(kvs[i]), kvs[i+1])
 	}
 	if l2 > len(kvs) {
-		a.Set(toString(kvs[len(kvs)-1]), "<missing>")
+		a.Set(toString(kvs[len(kvs)-1]), MissingValuePlaceholder)
 	}
 }
 
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+)
+
+// nextFieldRE matches the beginning of the next "key [value]" pair after the closing bracket of a field value.
+var nextFieldRE = regexp.MustCompile(`^ [^\s\[\]]+ \[`)
+
+// Parse parses the string representation of an error as produced by Error.ErrorNoTrace() (or Error.Error() with
+// PrintStacktrace disabled) and returns the corresponding *Error:
+//
+//	errors.Parse("op [read] kind [I/O error] file [a.txt] cause [EOF]")
+//	--> same as errors.NoTrace("read", errors.K.IO, errors.Str("EOF"), "file", "a.txt")
+//
+// Nested errors are parsed recursively and must be separated according to the current NestedIndent and Separator
+// settings. Since the string representation does not carry type information, all field values are parsed as strings,
+// and a non-*Error cause is parsed as error created with Str(). Field values that contain a closing bracket followed by
+// a string that looks like a "key [" prefix cannot be parsed unambiguously.
+//
+// Returns an error of kind K.Invalid if the string cannot be parsed.
+func Parse(s string) (*Error, error) {
+	var res, last *Error
+	rest := s
+	for depth := 1; ; depth++ {
+		// nested errors are separated like in the formatter, which depends on NestedIndent
+		seg, next, found := strings.Cut(rest, CauseKey+nestedSeparator(depth))
+		e, err := parseFields(strings.TrimSuffix(seg, " "))
+		if err != nil {
+			return nil, E("parse", K.Invalid, err, "text", s)
+		}
+		if res == nil {
+			res = e
+		} else {
+			last.cause = e
+		}
+		last = e
+		if !found {
+			break
+		}
+		rest = next
+	}
+	return res, nil
+}
+
+// parseFields parses a sequence of "key [value]" pairs separated by a single space into a new error.
+func parseFields(s string) (*Error, error) {
+	if s == "" {
+		return nil, NoTrace(K.Invalid, "reason", "empty error")
+	}
+
+	e := &Error{}
+	pos := 0
+	for pos < len(s) {
+		open := strings.Index(s[pos:], " [")
+		if open <= 0 {
+			return nil, NoTrace(K.Invalid, "reason", "missing field value", "pos", pos)
+		}
+		key := s[pos : pos+open]
+		valStart := pos + open + 2
+
+		valEnd := -1
+		for i := valStart; i < len(s); i++ {
+			if s[i] == ']' && (i+1 == len(s) || nextFieldRE.MatchString(s[i+1:])) {
+				valEnd = i
+				break
+			}
+		}
+		if valEnd < 0 {
+			return nil, NoTrace(K.Invalid, "reason", "unterminated field value", "key", key)
+		}
+
+		_ = e.With(key, s[valStart:valEnd])
+		pos = valEnd + 1
+		if pos < len(s) {
+			pos++ // skip the space separator
+		}
+	}
+	return e, nil
+}
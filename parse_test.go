@@ -0,0 +1,95 @@
+package errors_test
+
+import (
+	"encoding"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		err  *errors.Error
+		want *errors.Error
+	}{
+		{errors.NoTrace(), errors.NoTrace(errors.K.Other)},
+		{errors.NoTrace("read"), errors.NoTrace("read", errors.K.Other)},
+		{
+			errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt", "count", 2),
+			errors.NoTrace("read", errors.K.IO, errors.Str("EOF"), "file", "a.txt", "count", "2"),
+		},
+		{
+			errors.NoTrace("read", errors.K.IO, "path", "[a] [b]", "list", "[1 2]"),
+			errors.NoTrace("read", errors.K.IO, "path", "[a] [b]", "list", "[1 2]"),
+		},
+		{
+			errors.NoTrace("load", errors.NoTrace("read", errors.K.NotExist, io.EOF, "file", "a.txt"), "user", "joe"),
+			errors.NoTrace("load", errors.K.NotExist, "user", "joe",
+				errors.NoTrace("read", errors.K.NotExist, errors.Str("EOF"), "file", "a.txt")),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			parsed, err := errors.Parse(test.err.ErrorNoTrace())
+			require.NoError(t, err)
+			require.True(t, test.want.Equal(parsed), "want %#v\ngot  %#v", test.want, parsed)
+			require.Equal(t, test.err.ErrorNoTrace(), parsed.ErrorNoTrace())
+		})
+	}
+}
+
+func TestParse_failures(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"op",
+		"op [read",
+		"op [read] kind",
+		"op [read] cause:\n\t",
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := errors.Parse(s)
+			require.Error(t, err)
+			require.True(t, errors.IsKind(errors.K.Invalid, err))
+			require.Equal(t, s, errors.Field(err, "text"))
+		})
+	}
+}
+
+func TestError_MarshalText(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	var _ encoding.TextMarshaler = (*errors.Error)(nil)
+	var _ encoding.TextUnmarshaler = (*errors.Error)(nil)
+
+	e := errors.E("load", errors.E("read", errors.K.NotExist, io.EOF, "file", "a.txt"), "user", "joe")
+	text, err := e.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, e.ErrorNoTrace(), string(text))
+
+	var unmarshalled errors.Error
+	require.NoError(t, unmarshalled.UnmarshalText(text))
+	require.Equal(t, e.ErrorNoTrace(), unmarshalled.ErrorNoTrace())
+	require.Equal(t, "a.txt", unmarshalled.Field("file"))
+
+	require.Error(t, unmarshalled.UnmarshalText([]byte("invalid")))
+}
+
+func TestError_MarshalText_NestedIndent(t *testing.T) {
+	defer func() { errors.NestedIndent = "" }()
+	errors.NestedIndent = "  "
+
+	e := errors.NoTrace("fetch", errors.NoTrace("read", errors.K.IO, errors.NoTrace("open", io.EOF, "file", "a.txt")))
+	text, err := e.MarshalText()
+	require.NoError(t, err)
+
+	var unmarshalled errors.Error
+	require.NoError(t, unmarshalled.UnmarshalText(text))
+	require.Equal(t, 3, unmarshalled.Depth())
+	require.Equal(t, string(text), unmarshalled.ErrorNoTrace())
+	require.Equal(t, "a.txt", errors.GetRoot(&unmarshalled).Field("file"))
+}
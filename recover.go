@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"fmt"
+)
+
+// Recover recovers from a panic and assigns an error of kind K.Internal created with FromPanic(recovered, args...) to
+// *errp. It must be deferred directly, since recover() has no effect otherwise:
+//
+//	func process() (err error) {
+//		defer errors.Recover(&err, "process", "job", id)
+//		...
+//	}
+//
+// Any error previously assigned to *errp is replaced. Recover does nothing if there is no panic. If errp is nil, the
+// panic is propagated with the recovered value.
+func Recover(errp *error, args ...interface{}) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if errp == nil {
+		panic(r)
+	}
+	*errp = FromPanic(r, args...)
+}
+
+// FromPanic creates an error of kind K.Internal with E(args...) from the given value returned by recover():
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			log.Error("job failed", errors.FromPanic(r, "process", "job", id))
+//		}
+//	}()
+//
+// If the recovered value is an error, it becomes the cause of the returned error. Otherwise, it is stored in the
+// "panic" field - as is if it is a string, formatted with fmt.Sprint() otherwise. When called during a panic, i.e. in
+// a deferred function, the stacktrace of the error starts with the function that panicked rather than the deferred
+// function. The stacktrace is captured regardless of StacktraceSampleRate().
+func FromPanic(recovered interface{}, args ...interface{}) *Error {
+	e := newError(args).WithKind(K.Internal)
+	switch r := recovered.(type) {
+	case error:
+		_ = e.WithCause(r)
+	case string:
+		_ = e.With("panic", r)
+	default:
+		_ = e.With("panic", fmt.Sprint(r))
+	}
+	if PopulateStacktrace() {
+		e.populatePanicStack()
+	}
+	return created(e)
+}
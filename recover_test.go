@@ -0,0 +1,94 @@
+package errors_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func panicWith(val interface{}) (err error) {
+	defer errors.Recover(&err, "process", "job", 42)
+	panic(val)
+}
+
+func panicNilPointer() (err error) {
+	defer errors.Recover(&err, "process")
+	var m *struct{ field int }
+	m.field = 1
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	err := panicWith("boom")
+	require.Equal(t, "op [process] kind [internal error] job [42] panic [boom]", errors.ClearStacktrace(err).Error())
+
+	err = panicWith(io.EOF)
+	require.Equal(t, "op [process] kind [internal error] job [42] cause [EOF]", errors.ClearStacktrace(err).Error())
+
+	err = func() (err error) {
+		defer errors.Recover(&err, "process")
+		return io.EOF
+	}()
+	require.Equal(t, io.EOF, err)
+
+	require.PanicsWithValue(t, "boom", func() {
+		defer errors.Recover(nil)
+		panic("boom")
+	})
+}
+
+func TestRecover_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	for _, fn := range []func() error{
+		func() error { return panicWith("boom") },
+		panicNilPointer,
+	} {
+		cs := fn().(*errors.Error).CallStack()
+		require.NotEmpty(t, cs)
+		top := cs[0].Frame().Function
+		require.True(t, strings.HasSuffix(top, ".panicWith") || strings.HasSuffix(top, ".panicNilPointer"), cs.String())
+	}
+}
+
+func TestFromPanic(t *testing.T) {
+	err := errors.FromPanic("boom", "process")
+	require.Equal(t, "op [process] kind [internal error] panic [boom]", errors.ClearStacktrace(err).Error())
+
+	err = errors.FromPanic(io.EOF, "process")
+	require.Equal(t, "op [process] kind [internal error] cause [EOF]", errors.ClearStacktrace(err).Error())
+
+	err = errors.FromPanic(struct{ a, b int }{1, 2})
+	require.Equal(t, "kind [internal error] panic [{1 2}]", errors.ClearStacktrace(err).Error())
+}
+
+func TestFromPanic_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	err := func() (err *errors.Error) {
+		defer func() {
+			err = errors.FromPanic(recover())
+		}()
+		derefNil()
+		return nil
+	}()
+	cs := err.CallStack()
+	require.NotEmpty(t, cs)
+	require.True(t, strings.HasSuffix(cs[0].Frame().Function, ".derefNil"), cs.String())
+
+	// outside of a panic, the stack starts with the caller of FromPanic
+	cs = errors.FromPanic("boom").CallStack()
+	require.NotEmpty(t, cs)
+	require.True(t, strings.HasSuffix(cs[0].Frame().Function, ".TestFromPanic_Stacktrace"), cs.String())
+}
+
+func derefNil() {
+	var m *struct{ field int }
+	m.field = 1
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"strings"
+	"text/template"
+)
+
+// RenderData is the data view of an error that is passed to the template in Error.Render().
+type RenderData struct {
+	// Op is the operation of the error.
+	Op string
+	// Kind is the effective kind of the error.
+	Kind Kind
+	// Fields are the additional fields of the error, excluding op, kind and cause.
+	Fields map[string]interface{}
+	// Keys are the keys of Fields in the order they were added to the error.
+	Keys []string
+	// Text is the string representation of the error without stacktrace.
+	Text string
+	// Cause is the view of the cause of the error or nil if the error has no cause. If the cause is not an *Error,
+	// only its Text is set.
+	Cause *RenderData
+	// Stack is the call stack of the error, coalesced with the call stacks of nested errors. It is only set for the
+	// outermost error and empty if the error has no stacktrace.
+	Stack []RenderFrame
+}
+
+// RenderFrame is a single frame of the call stack in RenderData.
+type RenderFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Render executes the given text template against the data view of this error (see RenderData) and returns the result.
+// It allows to format errors for a specific audience, e.g. as Markdown for an issue tracker:
+//
+//	tmpl := template.Must(template.New("md").Parse(
+//		"**{{.Op}}** failed: _{{.Kind}}_\n{{range .Keys}}* {{.}}: `{{index $.Fields .}}`\n{{end}}"))
+//	s, err := e.Render(tmpl)
+//
+// Returns an error of kind K.Invalid if e or tmpl is nil or the execution of the template fails.
+func (e *Error) Render(tmpl *template.Template) (string, error) {
+	if e == nil {
+		return "", NoTrace("render", K.Invalid, "reason", "nil error")
+	}
+	if tmpl == nil {
+		return "", NoTrace("render", K.Invalid, "reason", "nil template")
+	}
+	data := e.renderData()
+	for _, call := range e.CallStack() {
+		frame := call.Frame()
+		data.Stack = append(data.Stack, RenderFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+	}
+
+	sb := strings.Builder{}
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", NoTrace("render", K.Invalid, err, "template", tmpl.Name())
+	}
+	return sb.String(), nil
+}
+
+// renderData returns the data view of this error without stack.
+func (e *Error) renderData() *RenderData {
+	data := &RenderData{
+		Op:     e.op,
+		Kind:   e.Kind(),
+		Fields: make(map[string]interface{}, len(e.fields)/2),
+		Keys:   e.Keys(),
+		Text:   e.ErrorNoTrace(),
+	}
+	for _, key := range data.Keys {
+		data.Fields[key], _ = e.fields.Get(key)
+	}
+	switch cause := e.cause.(type) {
+	case nil:
+	case *Error:
+		if cause != nil {
+			data.Cause = cause.renderData()
+		}
+	default:
+		data.Cause = &RenderData{Text: cause.Error()}
+	}
+	return data
+}
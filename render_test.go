@@ -0,0 +1,56 @@
+package errors_test
+
+import (
+	"io"
+	"strconv"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+const markdownTemplate = `**{{.Op}}** failed: _{{.Kind}}_
+{{range .Keys}}* {{.}}: ` + "`{{index $.Fields .}}`" + `
+{{end}}{{with .Cause}}> {{.Text}}{{end}}`
+
+const chainTemplate = `{{define "err"}}{{if .Op}}{{.Op}}{{else}}{{.Text}}{{end}}` +
+	`{{with .Cause}} <- {{template "err" .}}{{end}}{{end}}{{template "err" .}}`
+
+func TestError_Render(t *testing.T) {
+	err := errors.NoTrace("fetch", errors.K.Unavailable, "url", "http://a.b", "attempts", 3,
+		errors.NoTrace("read", errors.K.IO, io.EOF))
+
+	tmpl := template.Must(template.New("md").Parse(markdownTemplate))
+	s, rerr := err.Render(tmpl)
+	require.NoError(t, rerr)
+	require.Equal(t, "**fetch** failed: _service unavailable_\n"+
+		"* url: `http://a.b`\n"+
+		"* attempts: `3`\n"+
+		"> op [read] kind [I/O error] cause [EOF]", s)
+
+	tmpl = template.Must(template.New("chain").Parse(chainTemplate))
+	s, rerr = err.Render(tmpl)
+	require.NoError(t, rerr)
+	require.Equal(t, "fetch <- read <- EOF", s)
+
+	tmpl = template.Must(template.New("stack").Parse("{{len .Stack}}"))
+	err = errors.E("fetch")
+	s, rerr = err.Render(tmpl)
+	require.NoError(t, rerr)
+	require.Equal(t, strconv.Itoa(len(err.CallStack())), s)
+}
+
+func TestError_Render_invalid(t *testing.T) {
+	var nilErr *errors.Error
+	_, err := nilErr.Render(template.Must(template.New("t").Parse("{{.Op}}")))
+	require.True(t, errors.IsKind(errors.K.Invalid, err))
+
+	_, err = errors.NoTrace("fetch").Render(nil)
+	require.True(t, errors.IsKind(errors.K.Invalid, err))
+
+	_, err = errors.NoTrace("fetch").Render(template.Must(template.New("t").Parse("{{.Unknown}}")))
+	require.True(t, errors.IsKind(errors.K.Invalid, err))
+	require.Equal(t, "t", errors.Field(err, "template"))
+}
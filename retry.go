@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// RetryBackoff is the initial wait time between attempts in Retry(). It is doubled after every attempt up to
+// RetryMaxBackoff.
+var RetryBackoff = 100 * time.Millisecond
+
+// RetryMaxBackoff is the maximum wait time between attempts in Retry().
+var RetryMaxBackoff = 10 * time.Second
+
+// IsRetryable reports whether the operation that failed with the given error may succeed if it is retried. This is the
+// case if
+//   - a retry duration is set with WithRetryAfter()
+//   - the "temporary" or "timeout" field is true - see Capture()
+//   - the error is classified as K.Timeout, K.Unavailable or K.IO - see Classify()
+//
+// Returns false if err is nil.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := RetryAfter(err); ok {
+		return true
+	}
+	if Field(err, "temporary") == true || Field(err, "timeout") == true {
+		return true
+	}
+	switch Classify(err) {
+	case K.Timeout, K.Unavailable, K.IO:
+		return true
+	}
+	return false
+}
+
+// Retry calls fn until it succeeds, returns an error that is not retryable according to IsRetryable(), or the given
+// number of attempts is exhausted. Between attempts, Retry waits for the duration set with WithRetryAfter() on the
+// error if available, or an exponentially growing backoff otherwise - see RetryBackoff and RetryMaxBackoff:
+//
+//	err := errors.Retry(ctx, 5, func() error {
+//		return upload(ctx, file)
+//	})
+//
+// On failure, the last error is returned wrapped in an error with op "retry" and the number of attempts in the
+// "attempts" field. If ctx is done before fn succeeds, an error created with FromContext() is returned instead, with
+// the last error (if any) as cause. An attempts value smaller than 1 is treated as 1.
+func Retry(ctx context.Context, attempts int, fn func() error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	backoff := RetryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if cerr := FromContext(ctx, "retry", err, "attempts", attempt-1); cerr != nil {
+			return cerr
+		}
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts || !IsRetryable(err) {
+			return E("retry", err, "attempts", attempt)
+		}
+
+		wait, ok := RetryAfter(err)
+		if !ok {
+			wait = backoff
+			backoff *= 2
+			if backoff > RetryMaxBackoff {
+				backoff = RetryMaxBackoff
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, true},
+		{context.DeadlineExceeded, true},
+		{errors.NoTrace("op"), false},
+		{errors.NoTrace("op", errors.K.Invalid), false},
+		{errors.NoTrace("op", errors.K.NotExist), false},
+		{errors.NoTrace("op", errors.K.Unavailable), true},
+		{errors.NoTrace("op", errors.K.Timeout), true},
+		{errors.NoTrace("op", errors.NoTrace(errors.K.IO)), true},
+		{errors.NoTrace("op").WithRetryAfter(time.Second), true},
+		{errors.NoTrace("op", "temporary", true), true},
+		{errors.NoTrace("op", "timeout", false), false},
+	}
+	for _, test := range tests {
+		t.Run(errors.Summary(test.err), func(t *testing.T) {
+			require.Equal(t, test.want, errors.IsRetryable(test.err))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	defer func(b time.Duration) { errors.RetryBackoff = b }(errors.RetryBackoff)
+	errors.RetryBackoff = time.Millisecond
+
+	ctx := context.Background()
+
+	// success after retries
+	calls := 0
+	err := errors.Retry(ctx, 5, func() error {
+		calls++
+		if calls < 3 {
+			return errors.NoTrace("upload", errors.K.Unavailable)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+
+	// attempts exhausted
+	calls = 0
+	err = errors.Retry(ctx, 3, func() error {
+		calls++
+		return errors.NoTrace("upload", errors.K.Unavailable)
+	})
+	require.Equal(t, 3, calls)
+	require.Equal(t, "op [retry] kind [service unavailable] attempts [3] cause:\n\top [upload] kind [service unavailable]", errors.ClearStacktrace(err).Error())
+
+	// non-retryable error
+	calls = 0
+	err = errors.Retry(ctx, 3, func() error {
+		calls++
+		return errors.NoTrace("upload", errors.K.Invalid)
+	})
+	require.Equal(t, 1, calls)
+	require.Equal(t, 1, errors.Field(err, "attempts"))
+	require.True(t, errors.IsKind(errors.K.Invalid, err))
+
+	// retry after
+	calls = 0
+	start := time.Now()
+	_ = errors.Retry(ctx, 2, func() error {
+		calls++
+		return errors.NoTrace("upload", errors.K.Unavailable).WithRetryAfter(50 * time.Millisecond)
+	})
+	require.Equal(t, 2, calls)
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRetry_context(t *testing.T) {
+	defer func(b time.Duration) { errors.RetryBackoff = b }(errors.RetryBackoff)
+	errors.RetryBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	err := errors.Retry(ctx, 5, func() error {
+		calls++
+		return errors.NoTrace("upload", errors.K.Unavailable)
+	})
+	require.Equal(t, 1, calls)
+	require.True(t, errors.IsKind(errors.K.Cancelled, err))
+	require.True(t, errors.IsOp("upload", err)) // the last error is the cause
+
+	// done before the first attempt
+	calls = 0
+	err = errors.Retry(ctx, 5, func() error {
+		calls++
+		return nil
+	})
+	require.Equal(t, 0, calls)
+	require.True(t, errors.IsKind(errors.K.Cancelled, err))
+}
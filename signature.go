@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// Signature returns a string that identifies the "shape" of the given error independently of instance-specific data
+// like field values, so that errors originating from the same code path have the same signature. The signature
+// consists of the op and kind of each *Error in the cause chain and the type of the innermost non-*Error cause:
+//
+//	errors.E("fetch", errors.E("read", errors.K.IO, io.EOF, "file", f))
+//	--> "fetch [I/O error] <- read [I/O error] <- *errors.errorString"
+//
+// Returns "" if err is nil.
+func Signature(err error) string {
+	if err == nil {
+		return ""
+	}
+	sb := strings.Builder{}
+	for err != nil {
+		if sb.Len() > 0 {
+			sb.WriteString(" <- ")
+		}
+		e, ok := err.(*Error)
+		if !ok || e == nil {
+			sb.WriteString(fmt.Sprintf("%T", err))
+			break
+		}
+		if e.op != "" {
+			sb.WriteString(e.op)
+			sb.WriteString(" ")
+		}
+		sb.WriteString("[")
+		sb.WriteString(string(e.Kind()))
+		sb.WriteString("]")
+		err = e.cause
+	}
+	return sb.String()
+}
+
+// Hash returns a 64-bit FNV-1a hash of the Signature() of the given error. Since the signature does not depend on
+// instance-specific data and the hash function is stable, the hash is the same for errors originating from the same
+// code path, also across process runs. It may therefore be used to shard or sample errors consistently:
+//
+//	if errors.Hash(err)%uint64(workers) == uint64(worker) {
+//		report(err)
+//	}
+//
+// Returns 0 if err is nil.
+func Hash(err error) uint64 {
+	if err == nil {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(Signature(err)))
+	return h.Sum64()
+}
+
+// Hash returns the result of calling the package-level Hash() function on this error. Returns 0 if e is nil.
+func (e *Error) Hash() uint64 {
+	if e == nil {
+		return 0
+	}
+	return Hash(e)
+}
+
+// GroupBySignature groups the given errors by their Signature(). The errors of each group retain their order in errs.
+// Nil errors are ignored.
+func GroupBySignature(errs []error) map[string][]error {
+	res := make(map[string][]error)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		sig := Signature(err)
+		res[sig] = append(res[sig], err)
+	}
+	return res
+}
+
+// SignatureCount is the number of errors with a given signature. See TopSignatures.
+type SignatureCount struct {
+	Signature string
+	Count     int
+}
+
+// TopSignatures returns the n most frequent signatures of the given errors with their counts, ordered by descending
+// count and then by signature. Returns all signatures if n is smaller than 1 or larger than the number of signatures.
+// Nil errors are ignored.
+func TopSignatures(errs []error, n int) []SignatureCount {
+	groups := GroupBySignature(errs)
+	res := make([]SignatureCount, 0, len(groups))
+	for sig, group := range groups {
+		res = append(res, SignatureCount{Signature: sig, Count: len(group)})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Signature < res[j].Signature
+	})
+	if n > 0 && n < len(res) {
+		res = res[:n]
+	}
+	return res
+}
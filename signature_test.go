@@ -0,0 +1,99 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestSignature(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, "*errors.errorString"},
+		{errors.NoTrace(), "[unclassified error]"},
+		{errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt"), "read [I/O error] <- *errors.errorString"},
+		{
+			errors.NoTrace("fetch", errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt")),
+			"fetch [I/O error] <- read [I/O error] <- *errors.errorString",
+		},
+		{errors.NoTrace("fetch", errors.NoTrace("read", errors.K.NotExist)), "fetch [item does not exist] <- read [item does not exist]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, errors.Signature(test.err))
+		})
+	}
+
+	// field values and stacktraces don't affect the signature
+	require.Equal(t,
+		errors.Signature(errors.E("read", errors.K.IO, io.EOF, "file", "a.txt")),
+		errors.Signature(errors.NoTrace("read", errors.K.IO, io.ErrUnexpectedEOF, "file", "b.txt")))
+}
+
+func TestHash(t *testing.T) {
+	err := errors.NoTrace("read", errors.K.IO, io.EOF, "file", "a.txt")
+
+	// stable across process runs
+	require.Equal(t, uint64(9837589226764668873), errors.Hash(err))
+	require.Equal(t, errors.Hash(err), err.Hash())
+	require.Equal(t, errors.Hash(err), errors.Hash(errors.E("read", errors.K.IO, io.ErrUnexpectedEOF, "file", "b.txt")))
+	require.NotEqual(t, errors.Hash(err), errors.Hash(errors.NoTrace("write", errors.K.IO, io.EOF)))
+	require.NotEqual(t, uint64(0), errors.Hash(io.EOF))
+
+	require.Equal(t, uint64(0), errors.Hash(nil))
+	require.Equal(t, uint64(0), (*errors.Error)(nil).Hash())
+}
+
+func TestGroupBySignature(t *testing.T) {
+	read := func(file string) error {
+		return errors.NoTrace("read", errors.K.IO, io.EOF, "file", file)
+	}
+	notFound := func(user string) error {
+		return errors.NoTrace("lookup", errors.K.NotExist, "user", user)
+	}
+
+	errs := []error{read("a"), notFound("joe"), read("b"), nil, read("c"), notFound("jane"), io.EOF}
+
+	groups := errors.GroupBySignature(errs)
+	require.Len(t, groups, 3)
+	require.Equal(t, []error{errs[0], errs[2], errs[4]}, groups["read [I/O error] <- *errors.errorString"])
+	require.Equal(t, []error{errs[1], errs[5]}, groups["lookup [item does not exist]"])
+	require.Equal(t, []error{io.EOF}, groups["*errors.errorString"])
+
+	require.Empty(t, errors.GroupBySignature(nil))
+
+	tests := []struct {
+		n    int
+		want []errors.SignatureCount
+	}{
+		{1, []errors.SignatureCount{{"read [I/O error] <- *errors.errorString", 3}}},
+		{2, []errors.SignatureCount{
+			{"read [I/O error] <- *errors.errorString", 3},
+			{"lookup [item does not exist]", 2},
+		}},
+		{0, []errors.SignatureCount{
+			{"read [I/O error] <- *errors.errorString", 3},
+			{"lookup [item does not exist]", 2},
+			{"*errors.errorString", 1},
+		}},
+		{10, []errors.SignatureCount{
+			{"read [I/O error] <- *errors.errorString", 3},
+			{"lookup [item does not exist]", 2},
+			{"*errors.errorString", 1},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint("top", test.n), func(t *testing.T) {
+			require.Equal(t, test.want, errors.TopSignatures(errs, test.n))
+		})
+	}
+	require.Empty(t, errors.TopSignatures(nil, 3))
+}
@@ -0,0 +1,97 @@
+//go:build go1.21
+
+package errors
+
+import (
+	"bytes"
+	"log/slog"
+	"strconv"
+)
+
+// LogStacktrace controls whether the stacktrace is included as "stacktrace" attribute in the slog.Value returned by
+// Error.LogValue().
+var LogStacktrace = true
+
+// LogValue implements slog.LogValuer and returns the error as a structured group with the op, kind, fields and cause of
+// the error, ordered according to DefaultFieldOrder. Nested errors are logged as nested groups:
+//
+//	slog.Error("failed", "err", errors.E("read", errors.K.IO, io.EOF, "file", f))
+//	--> level=ERROR msg=failed err.op=read err.kind="I/O error" err.file=a.txt err.cause=EOF
+//
+// The stacktrace is added as "stacktrace" attribute if LogStacktrace is enabled.
+func (e *Error) LogValue() slog.Value {
+	if e == nil {
+		return slog.StringValue("")
+	}
+	return slog.GroupValue(e.Attrs()...)
+}
+
+// Attrs returns the op, kind, fields and cause of the error as slog attributes, ordered according to DefaultFieldOrder.
+// In contrast to LogValue(), which returns a single group, the attributes can be added individually to a log record:
+//
+//	logger.LogAttrs(ctx, slog.LevelError, "read failed", err.Attrs()...)
+//
+// Field values are converted with slog.Any(), which produces typed attributes for strings, numbers, bools, time.Time and
+// time.Duration. A nested *Error cause is returned as group. The stacktrace is added as "stacktrace" attribute if
+// LogStacktrace is enabled. Returns nil if e is nil.
+func (e *Error) Attrs() []slog.Attr {
+	if e == nil {
+		return nil
+	}
+	return e.logAttrs(true)
+}
+
+func (e *Error) logAttrs(logStack bool) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 3+len(e.fields)/2)
+
+	_ = e.writeFields(DefaultFieldOrder, func(key interface{}, val interface{}) error {
+		k := key.(string)
+		switch v := val.(type) {
+		case Kind:
+			attrs = append(attrs, slog.String(k, string(v)))
+		case *Error:
+			if k == CauseKey {
+				attrs = append(attrs, slog.Attr{Key: k, Value: slog.GroupValue(v.logAttrs(false)...)})
+			} else {
+				attrs = append(attrs, slog.Any(k, v))
+			}
+		case error:
+			attrs = append(attrs, slog.String(k, v.Error()))
+		default:
+			attrs = append(attrs, slog.Any(k, v))
+		}
+		return nil
+	})
+
+	if logStack && LogStacktrace && !e.ignoreStack && e.hasStack() {
+		b := new(bytes.Buffer)
+		e.printStack(b)
+		attrs = append(attrs, slog.String("stacktrace", b.String()))
+	}
+	return attrs
+}
+
+// LogValue implements slog.LogValuer and returns the error list as a structured group with the number of errors as
+// "count" and the errors as "errors" group, keyed by their index in the list. *Error instances are logged as groups
+// (see Error.LogValue()), other errors with their error message:
+//
+//	slog.Error("batch failed", "err", errors.Append(errors.E("read", errors.K.IO), io.EOF))
+//	--> level=ERROR msg="batch failed" err.count=2 err.errors.0.op=read err.errors.0.kind="I/O error" err.errors.1=EOF
+func (e *ErrorList) LogValue() slog.Value {
+	if e == nil {
+		return slog.StringValue("")
+	}
+	errs := e.Errors // local copy to prevent concurrency issues
+	attrs := make([]slog.Attr, len(errs))
+	for idx, err := range errs {
+		key := strconv.Itoa(idx)
+		if ee, ok := err.(*Error); ok {
+			attrs[idx] = slog.Attr{Key: key, Value: ee.LogValue()}
+		} else {
+			attrs[idx] = slog.String(key, err.Error())
+		}
+	}
+	return slog.GroupValue(
+		slog.Int("count", len(errs)),
+		slog.Attr{Key: "errors", Value: slog.GroupValue(attrs...)})
+}
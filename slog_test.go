@@ -0,0 +1,148 @@
+//go:build go1.21
+
+package errors_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestError_LogValue(t *testing.T) {
+	log := func(err error) string {
+		b := new(bytes.Buffer)
+		logger := slog.New(slog.NewTextHandler(b, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+		logger.Error("failed", "err", err)
+		return strings.TrimSpace(b.String())
+	}
+
+	err := errors.NoTrace("read", errors.K.IO, errors.NoTrace("open", io.EOF, "attempt", 2), "file", "a.txt")
+	require.Equal(t,
+		`level=ERROR msg=failed err.op=read err.kind="I/O error" err.file=a.txt err.cause.op=open err.cause.kind="unclassified error" err.cause.attempt=2 err.cause.cause=EOF`,
+		log(err))
+
+	require.Equal(t, `level=ERROR msg=failed err.kind="unclassified error"`, log(errors.NoTrace()))
+
+	var nilErr *errors.Error
+	require.Equal(t, `level=ERROR msg=failed err=""`, log(nilErr))
+}
+
+func TestErrorList_LogValue(t *testing.T) {
+	logText := func(err error) string {
+		b := new(bytes.Buffer)
+		logger := slog.New(slog.NewTextHandler(b, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+		logger.Error("batch failed", "err", err)
+		return strings.TrimSpace(b.String())
+	}
+	logJSON := func(err error) string {
+		b := new(bytes.Buffer)
+		logger := slog.New(slog.NewJSONHandler(b, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+		logger.Error("batch failed", "err", err)
+		return strings.TrimSpace(b.String())
+	}
+
+	list := errors.Append(errors.NoTrace("read", errors.K.IO, "file", "a.txt"), io.EOF)
+	require.Equal(t,
+		`level=ERROR msg="batch failed" err.count=2 err.errors.0.op=read err.errors.0.kind="I/O error" err.errors.0.file=a.txt err.errors.1=EOF`,
+		logText(list))
+	require.Equal(t,
+		`{"level":"ERROR","msg":"batch failed","err":{"count":2,"errors":{"0":{"op":"read","kind":"I/O error","file":"a.txt"},"1":"EOF"}}}`,
+		logJSON(list))
+
+	require.Equal(t, `level=ERROR msg="batch failed" err.count=0`, logText(&errors.ErrorList{}))
+
+	var nilList *errors.ErrorList
+	require.Equal(t, `level=ERROR msg="batch failed" err=""`, logText(nilList))
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+func TestError_Attrs(t *testing.T) {
+	var nilErr *errors.Error
+	require.Nil(t, nilErr.Attrs())
+
+	err := errors.NoTrace("read", errors.K.IO, io.EOF,
+		"file", "a.txt",
+		"attempt", 2,
+		"ratio", 0.5,
+		"ok", true,
+		"timeout", 3*time.Second)
+	attrs := err.Attrs()
+
+	type kv struct {
+		key  string
+		kind slog.Kind
+		val  interface{}
+	}
+	var got []kv
+	for _, a := range attrs {
+		got = append(got, kv{a.Key, a.Value.Kind(), a.Value.Any()})
+	}
+	require.Equal(t, []kv{
+		{"op", slog.KindString, "read"},
+		{"kind", slog.KindString, "I/O error"},
+		{"file", slog.KindString, "a.txt"},
+		{"attempt", slog.KindInt64, int64(2)},
+		{"ratio", slog.KindFloat64, 0.5},
+		{"ok", slog.KindBool, true},
+		{"timeout", slog.KindDuration, 3 * time.Second},
+		{"cause", slog.KindString, "EOF"},
+	}, got)
+
+	b := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(b, nil))
+	logger.LogAttrs(context.Background(), slog.LevelError, "failed", errors.NoTrace("read", io.EOF).Attrs()...)
+	require.Contains(t, b.String(), `level=ERROR msg=failed op=read kind="unclassified error" cause=EOF`)
+}
+
+func TestError_LogValue_Stacktrace(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer func(logStack bool) {
+		errors.LogStacktrace = logStack
+	}(errors.LogStacktrace)
+
+	find := func(attrs []slog.Attr, key string) (slog.Attr, bool) {
+		for _, a := range attrs {
+			if a.Key == key {
+				return a, true
+			}
+		}
+		return slog.Attr{}, false
+	}
+
+	err := errors.E("read", errors.K.IO, errors.E("open"))
+
+	errors.LogStacktrace = true
+	attrs := err.LogValue().Group()
+	stack, ok := find(attrs, "stacktrace")
+	require.True(t, ok)
+	require.Contains(t, stack.Value.String(), "TestError_LogValue_Stacktrace()")
+	cause, ok := find(attrs, "cause")
+	require.True(t, ok)
+	_, ok = find(cause.Value.Group(), "stacktrace")
+	require.False(t, ok)
+
+	stack, ok = find(err.Attrs(), "stacktrace")
+	require.True(t, ok)
+	require.Contains(t, stack.Value.String(), "TestError_LogValue_Stacktrace()")
+
+	errors.LogStacktrace = false
+	_, ok = find(err.LogValue().Group(), "stacktrace")
+	require.False(t, ok)
+	_, ok = find(err.Attrs(), "stacktrace")
+	require.False(t, ok)
+}
@@ -3,8 +3,12 @@
 package errors
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
 
 	gostack "github.com/eluv-io/stack"
 )
@@ -26,6 +30,32 @@ func (e *Error) populateStack() {
 	e.pcs = gostack.Callers(2)
 }
 
+// populatePanicStack populates the stack like populateStack, but is intended to be called (indirectly) from a deferred
+// function that recovered from a panic: it removes the frames of the deferred function and of the runtime's panic
+// handling, so that the stack starts with the function that panicked. If the call stack contains no panic frames, the
+// stack starts with the caller of the function calling populatePanicStack.
+func (e *Error) populatePanicStack() {
+	pcs := gostack.Callers(1)
+	panicking := false
+	for i, pc := range pcs {
+		name := ""
+		if fn := runtime.FuncForPC(pc - 1); fn != nil {
+			name = fn.Name()
+		}
+		if name == "runtime.gopanic" {
+			panicking = true
+		} else if panicking && !strings.HasPrefix(name, "runtime.") {
+			// keep the last runtime frame: the first frame is skipped when the trace is created
+			e.pcs = pcs[i-1:]
+			e.trace = nil
+			return
+		}
+	}
+	e.pcs = pcs
+	e.trace = nil
+	e.dropStackFrames(1)
+}
+
 // dropStackFrames removes the top n stack frames.
 func (e *Error) dropStackFrames(n int) *Error {
 	if len(e.pcs) > n {
@@ -38,25 +68,120 @@ func (e *Error) dropStackFrames(n int) *Error {
 // printStack formats and prints the stack for this Error to the given buffer. It should be called from the Error's
 // Error method.
 func (e *Error) printStack(b *bytes.Buffer) {
-	trace := e.coalesceStack()
+	lines := stackLines(e.coalesceStack())
+	source := ""
+	if StackSourceFrame {
+		source = toString(e.FieldDeep("source"))
+	}
 	if PrintStacktracePretty {
-		filenames := make([]string, len(trace))
-		max := 0
-		for i, call := range trace {
-			filenames[i] = fmt.Sprintf("%+v", call)
-			fl := len(filenames[i])
-			if max < fl {
+		max := len(source)
+		for _, line := range lines {
+			fl := len(line.file)
+			if line.filtered == 0 && max < fl {
 				max = fl
 			}
 		}
-		for i, call := range trace {
-			fmt.Fprintf(b, "\t%-*s %n()\n", max, filenames[i], call)
+		if source != "" {
+			fmt.Fprintf(b, "\t%-*s (source)\n", max, source)
+		}
+		for i, line := range lines {
+			if line.filtered > 0 {
+				fmt.Fprintf(b, "\t... (%d frames in %s)\n", line.filtered, line.filteredBy)
+				continue
+			}
+			fmt.Fprintf(b, "\t%-*s %n()\n", max, line.file, line.call)
+			if i == 0 {
+				printSourceLine(b, line.call)
+			}
+		}
+		return
+	}
+	if source != "" {
+		fmt.Fprintf(b, "\t%s\t(source)\n", source)
+	}
+	for i, line := range lines {
+		if line.filtered > 0 {
+			fmt.Fprintf(b, "\t... (%d frames in %s)\n", line.filtered, line.filteredBy)
+			continue
+		}
+		fmt.Fprintf(b, "\t%s\t%n()\n", line.file, line.call)
+		if i == 0 {
+			printSourceLine(b, line.call)
 		}
+	}
+}
+
+// printSourceLine prints the source code line of the given call if StackSourceLines is enabled. Does nothing if the
+// source file is not available.
+func printSourceLine(b *bytes.Buffer, call gostack.Call) {
+	if !StackSourceLines {
 		return
 	}
+	frame := call.Frame()
+	src, ok := readSourceLine(frame.File, frame.Line)
+	if !ok {
+		return
+	}
+	b.WriteString("\t>>> ")
+	b.WriteString(src)
+	b.WriteString("\n")
+}
+
+// readSourceLine returns the trimmed line with the given 1-based line number from the given file.
+func readSourceLine(file string, line int) (string, bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", false
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if n == line {
+			return strings.TrimSpace(scanner.Text()), true
+		}
+	}
+	return "", false
+}
+
+// stackLine is a line of a printed stacktrace: either a call with its formatted file name and line number, or a run of
+// consecutive calls that were removed by StackKeepModule or StackPathFilter.
+type stackLine struct {
+	file       string
+	call       gostack.Call
+	filtered   int    // the number of filtered calls
+	filteredBy string // the description of the filtered calls
+}
+
+// stackLines converts the given call stack to stack lines, applying StackKeepModule, StackPathFilter and
+// StackTrimPrefix.
+func stackLines(trace gostack.CallStack) []stackLine {
+	filter := StackPathFilter
+	keepModule := StackKeepModule
+	lines := make([]stackLine, 0, len(trace))
+	addFiltered := func(filteredBy string) {
+		if n := len(lines); n > 0 && lines[n-1].filteredBy == filteredBy {
+			lines[n-1].filtered++
+		} else {
+			lines = append(lines, stackLine{filtered: 1, filteredBy: filteredBy})
+		}
+	}
 	for _, call := range trace {
-		fmt.Fprintf(b, "\t%+v\t%[1]n()\n", call)
+		file := fmt.Sprintf("%+v", call)
+		if keepModule != "" && !strings.HasPrefix(file, keepModule) {
+			addFiltered("other modules")
+			continue
+		}
+		if filter != nil && filter(call.Frame().File) {
+			addFiltered("vendored code")
+			continue
+		}
+		if StackTrimPrefix != "" {
+			file = strings.TrimPrefix(file, StackTrimPrefix)
+		}
+		lines = append(lines, stackLine{file: file, call: call})
 	}
+	return lines
 }
 
 func (e *Error) coalesceStack() gostack.CallStack {
@@ -73,6 +198,17 @@ func (e *Error) coalesceStack() gostack.CallStack {
 	return ct
 }
 
+// CallStack returns the call stack of this error, coalesced with the call stacks of nested errors as printed in Error().
+// It allows to inspect or format the stack with the functions of the github.com/eluv-io/stack package. The returned
+// call stack must not be modified. Returns nil if e is nil or has no stacktrace, and always if the "errnostack" build
+// tag is set.
+func (e *Error) CallStack() gostack.CallStack {
+	if e == nil {
+		return nil
+	}
+	return e.coalesceStack()
+}
+
 // hasStack returns true if this error or any nested error has a stack trace, false otherwise.
 func (e *Error) hasStack() bool {
 	if e.pcs != nil {
@@ -0,0 +1,25 @@
+//go:build !errnostack
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSourceLine(t *testing.T) {
+	src, ok := readSourceLine("stack_pkg_test.go", 1)
+	require.True(t, ok)
+	require.Equal(t, "//go:build !errnostack", src)
+
+	src, ok = readSourceLine("stack_pkg_test.go", 3)
+	require.True(t, ok)
+	require.Equal(t, "package errors", src)
+
+	_, ok = readSourceLine("stack_pkg_test.go", 10000)
+	require.False(t, ok)
+
+	_, ok = readSourceLine("/does/not/exist.go", 1)
+	require.False(t, ok)
+}
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -101,6 +102,222 @@ func TestClearStacktrace(t *testing.T) {
 
 }
 
+func TestStackPathFilter(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer func() {
+		errors.StackPathFilter = nil
+	}()
+
+	errors.StackPathFilter = func(file string) bool {
+		return strings.HasSuffix(file, "/stack_test.go")
+	}
+
+	for _, psp := range []bool{true, false} {
+		errors.PrintStacktracePretty = psp
+		t.Run(fmt.Sprint("pretty", psp), func(t *testing.T) {
+			lines := strings.Split(strings.TrimSuffix(func1(false).Error(), "\n"), "\n")[4:]
+			require.Equal(t, 2, len(lines), lines)
+			require.Regexp(t, errorLineREs[0], lines[0])
+			require.Equal(t, "\t... (6 frames in vendored code)", lines[1])
+		})
+	}
+
+	errors.StackPathFilter = func(file string) bool {
+		return strings.HasSuffix(file, "/stack_with_long_filename_test.go")
+	}
+	errors.PrintStacktracePretty = true
+	lines := strings.Split(strings.TrimSuffix(func1(false).Error(), "\n"), "\n")[4:]
+	require.Len(t, lines, len(errorLines)-1)
+	require.Equal(t, "\t... (1 frames in vendored code)", lines[0])
+	// alignment only considers the remaining frames
+	require.Regexp(t, `^\tgithub.com/eluv-io/errors-go/stack_test.go:\d+ +func4\(\)$`, lines[1])
+	require.Equal(t, len(lines[1])-len("func4()"), strings.Index(lines[2], "func4()"))
+}
+
+func TestStackKeepModule(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer func() {
+		errors.StackKeepModule = ""
+	}()
+
+	errors.StackKeepModule = "github.com/eluv-io/errors-go/stack_test.go"
+	for _, psp := range []bool{true, false} {
+		errors.PrintStacktracePretty = psp
+		t.Run(fmt.Sprint("pretty", psp), func(t *testing.T) {
+			lines := strings.Split(strings.TrimSuffix(func1(false).Error(), "\n"), "\n")[4:]
+			require.Equal(t, len(errorLines)-1, len(lines), lines)
+			require.Equal(t, "\t... (1 frames in other modules)", lines[0])
+			for i, line := range lines[1:] {
+				require.Regexp(t, errorLineREs[i+1], line)
+			}
+		})
+	}
+
+	// alignment only considers the remaining frames
+	errors.PrintStacktracePretty = true
+	lines := strings.Split(strings.TrimSuffix(func1(false).Error(), "\n"), "\n")[4:]
+	require.Len(t, lines, len(errorLines)-1)
+	require.Regexp(t, `^\tgithub.com/eluv-io/errors-go/stack_test.go:\d+ func4\(\)$`, lines[1])
+
+	errors.StackKeepModule = "github.com/other/"
+	lines = strings.Split(strings.TrimSuffix(func1(false).Error(), "\n"), "\n")[4:]
+	require.Equal(t, []string{fmt.Sprintf("\t... (%d frames in other modules)", len(errorLines)-1)}, lines)
+}
+
+func TestStackTrimPrefix(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer func() {
+		errors.StackTrimPrefix = ""
+	}()
+
+	errors.StackTrimPrefix = "github.com/eluv-io/errors-go/"
+
+	for _, psp := range []bool{true, false} {
+		errors.PrintStacktracePretty = psp
+		t.Run(fmt.Sprint("pretty", psp), func(t *testing.T) {
+			lines := strings.Split(func1(false).Error(), "\n")[4:]
+			require.Equal(t, len(errorLines), len(lines))
+			for i, line := range lines {
+				re := strings.Replace(errorLines[i], "github.com/eluv-io/errors-go/", "", 1)
+				require.Regexp(t, "^"+re+"$", line)
+			}
+			if psp {
+				// alignment is based on the trimmed file names
+				require.Regexp(t, `^\tstack_with_long_filename_test.go:\d+ createErrorWithExtraLongFilename\(\)$`, lines[0])
+				require.Equal(t, strings.Index(lines[0], "createErrorWithExtraLongFilename"), strings.Index(lines[1], "func4"))
+			}
+		})
+	}
+}
+
+func TestStackSourceLines(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer func() {
+		errors.StackSourceLines = false
+	}()
+
+	errors.StackSourceLines = true
+	for _, psp := range []bool{true, false} {
+		errors.PrintStacktracePretty = psp
+		t.Run(fmt.Sprint("pretty", psp), func(t *testing.T) {
+			lines := strings.Split(func1(false).Error(), "\n")[4:]
+			require.Equal(t, len(errorLines)+1, len(lines))
+			require.Regexp(t, errorLineREs[0], lines[0])
+			require.Equal(t, `	>>> return errors.E("long-error", cause)`, lines[1])
+			for i, line := range lines[2:] {
+				require.Regexp(t, errorLineREs[i+1], line)
+			}
+		})
+	}
+
+	errors.StackSourceLines = false
+	require.NotContains(t, errors.E("op").Error(), ">>>")
+}
+
+func TestStackSourceFrame(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer func() {
+		errors.StackSourceFrame = false
+	}()
+
+	errors.StackSourceFrame = true
+	for _, psp := range []bool{true, false} {
+		errors.PrintStacktracePretty = psp
+		t.Run(fmt.Sprint("pretty", psp), func(t *testing.T) {
+			err := errors.E("render", errors.E("parse").WithSource("templates/inner.tmpl", 3)).
+				WithSource("templates/outer.tmpl", 12)
+			lines := strings.Split(err.Error(), "\n")
+			require.GreaterOrEqual(t, len(lines), 4, lines)
+			require.Regexp(t, `^\ttemplates/inner.tmpl:3\s+\(source\)$`, lines[2])
+			require.Regexp(t, `^\t.*stack_test.go:\d+\s+TestStackSourceFrame.func`, lines[3])
+		})
+	}
+
+	errors.StackSourceFrame = false
+	require.NotContains(t, errors.E("op").WithSource("a.tmpl", 1).Error(), "(source)")
+}
+
+func TestError_CallStack(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+
+	err := func1(false).(*errors.Error)
+	cs := err.CallStack()
+	require.Equal(t, len(errorLines)-1, len(cs)) // errorLines ends with an empty line
+	require.True(t, strings.HasSuffix(cs[0].Frame().Function, ".createErrorWithExtraLongFilename"), cs[0].Frame().Function)
+	require.True(t, strings.HasSuffix(cs[len(cs)-1].Frame().Function, ".TestError_CallStack"), cs[len(cs)-1].Frame().Function)
+
+	require.Nil(t, errors.NoTrace("op").CallStack())
+	require.Nil(t, (*errors.Error)(nil).CallStack())
+}
+
+func TestStacktraceSampleRate(t *testing.T) {
+	revert := enableStacktraces()
+	defer revert()
+	defer errors.SetStacktraceSampleRate(errors.StacktraceSampleRate())
+
+	countTraces := func() int {
+		count := 0
+		for i := 0; i < 1000; i++ {
+			err := errors.E("op", errors.K.IO)
+			s := err.Error()
+			if strings.Contains(s, "TestStacktraceSampleRate") {
+				count++
+			} else {
+				require.Equal(t, "op [op] kind [I/O error]", s)
+			}
+		}
+		return count
+	}
+
+	errors.SetStacktraceSampleRate(0)
+	require.Equal(t, 0, countTraces())
+
+	errors.SetStacktraceSampleRate(1)
+	require.Equal(t, 1000, countTraces())
+
+	errors.SetStacktraceSampleRate(0.5)
+	count := countTraces()
+	require.Greater(t, count, 350)
+	require.Less(t, count, 650)
+
+	// the rate may be changed concurrently with error creation
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			errors.SetStacktraceSampleRate(float64(i%2) / 2)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = errors.E("op")
+		}
+	}()
+	wg.Wait()
+}
+
+// TestE_populateStacktraceOff verifies that E() does no stack work when stacktrace population is disabled at runtime.
+func TestE_populateStacktraceOff(t *testing.T) {
+	defer errors.SetPopulateStacktrace(errors.PopulateStacktrace())
+	errors.SetPopulateStacktrace(false)
+
+	allocsE := testing.AllocsPerRun(100, func() {
+		_ = errors.E("op", errors.K.Invalid, "key", "val")
+	})
+	allocsNoTrace := testing.AllocsPerRun(100, func() {
+		_ = errors.NoTrace("op", errors.K.Invalid, "key", "val")
+	})
+	require.Equal(t, allocsNoTrace, allocsE)
+}
+
 func validateStacktrace(t *testing.T, got string) {
 	fmt.Println(got)
 	lines := strings.Split(got, "\n")
@@ -210,6 +427,41 @@ func doBenchmarkPrintStack(b *testing.B, pretty bool) {
 
 }
 
+/*
+	$ go test -v -bench "^BenchmarkE$" -run "^Benchmark" github.com/eluv-io/errors-go
+	goos: linux
+	goarch: amd64
+	pkg: github.com/eluv-io/errors-go
+	BenchmarkE/E-stack-on         	  622786	      2747 ns/op	    4352 B/op	       5 allocs/op
+	BenchmarkE/E-stack-off        	 2660623	       455.3 ns/op	     256 B/op	       4 allocs/op
+	BenchmarkE/NoTrace            	 2667938	       453.0 ns/op	     256 B/op	       4 allocs/op
+*/
+
+func BenchmarkE(b *testing.B) {
+	defer errors.SetPopulateStacktrace(errors.PopulateStacktrace())
+
+	b.Run("E-stack-on", func(b *testing.B) {
+		errors.SetPopulateStacktrace(true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = errors.E("op", errors.K.Invalid, "key", "val")
+		}
+	})
+	b.Run("E-stack-off", func(b *testing.B) {
+		errors.SetPopulateStacktrace(false)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = errors.E("op", errors.K.Invalid, "key", "val")
+		}
+	})
+	b.Run("NoTrace", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = errors.NoTrace("op", errors.K.Invalid, "key", "val")
+		}
+	})
+}
+
 func BenchmarkPopulateStack(b *testing.B) {
 	b.Run("stack.Trace", func(b *testing.B) {
 		b.ReportAllocs()
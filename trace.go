@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"context"
+)
+
+// TraceExtractor is an optional function that extracts the trace and span IDs of the current span from a context, e.g.
+// from an OpenTelemetry span context. It allows to correlate errors with traces without introducing a dependency on a
+// tracing library:
+//
+//	errors.TraceExtractor = func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	}
+//
+// The extracted IDs are added to errors created with FromContext() and FromSpanContext(). Empty IDs are ignored. Like
+// OnCreate, the extractor should be set once during program initialization and not be modified afterwards.
+var TraceExtractor func(ctx context.Context) (traceID, spanID string)
+
+// WithTrace sets the given trace and span IDs and returns this error instance for call chaining. The IDs are stored in
+// the "trace_id" and "span_id" fields. Empty IDs are ignored. See TraceIDs().
+func (e *Error) WithTrace(traceID, spanID string) *Error {
+	if e == nil {
+		return e
+	}
+	if traceID != "" {
+		e.fields.Set("trace_id", traceID)
+	}
+	if spanID != "" {
+		e.fields.Set("span_id", spanID)
+	}
+	return e
+}
+
+// TraceIDs returns the trace and span IDs set with WithTrace() on the given error or the first of its nested errors that
+// has a trace ID. Returns empty IDs and false if err is not an *Error or has no trace ID.
+func TraceIDs(err error) (traceID, spanID string, ok bool) {
+	var e interface{} = err
+	for {
+		ex, isErr := e.(*Error)
+		if !isErr || ex == nil {
+			return "", "", false
+		}
+		if tid, found := ex.fields.Get("trace_id"); found {
+			traceID = toString(tid)
+			if sid, found := ex.fields.Get("span_id"); found {
+				spanID = toString(sid)
+			}
+			return traceID, spanID, true
+		}
+		e = ex.cause
+	}
+}
+
+// FromSpanContext creates a new error with E(args...) and sets the trace and span IDs extracted from the given context
+// with TraceExtractor:
+//
+//	errors.FromSpanContext(ctx, "fetch", errors.K.Unavailable, "url", url)
+//	--> op [fetch] kind [service unavailable] url [https://...] trace_id [4bf92f...] span_id [00f067...]
+//
+// No trace IDs are set if TraceExtractor is nil or ctx is nil.
+func FromSpanContext(ctx context.Context, args ...interface{}) *Error {
+	return created(withTraceFrom(ctx, newErrorWithStack(args)))
+}
+
+// withTraceFrom sets the trace and span IDs extracted from ctx with TraceExtractor on the given error.
+func withTraceFrom(ctx context.Context, e *Error) *Error {
+	if TraceExtractor == nil || ctx == nil {
+		return e
+	}
+	traceID, spanID := TraceExtractor(ctx)
+	return e.WithTrace(traceID, spanID)
+}
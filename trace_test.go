@@ -0,0 +1,108 @@
+package errors_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+type traceKey struct{}
+
+type traceIDs struct {
+	traceID, spanID string
+}
+
+func withTraceExtractor(t *testing.T) {
+	errors.TraceExtractor = func(ctx context.Context) (string, string) {
+		ids, _ := ctx.Value(traceKey{}).(traceIDs)
+		return ids.traceID, ids.spanID
+	}
+	t.Cleanup(func() { errors.TraceExtractor = nil })
+}
+
+func TestWithTrace(t *testing.T) {
+	err := errors.NoTrace("read", errors.K.IO, io.EOF).WithTrace("t1", "s1")
+	require.Equal(t, "op [read] kind [I/O error] trace_id [t1] span_id [s1] cause [EOF]", err.Error())
+
+	traceID, spanID, ok := errors.TraceIDs(err)
+	require.True(t, ok)
+	require.Equal(t, "t1", traceID)
+	require.Equal(t, "s1", spanID)
+
+	// nested
+	traceID, spanID, ok = errors.TraceIDs(errors.NoTrace("fetch", err))
+	require.True(t, ok)
+	require.Equal(t, "t1", traceID)
+	require.Equal(t, "s1", spanID)
+
+	// empty IDs are ignored
+	err = errors.NoTrace("read").WithTrace("t1", "")
+	require.Equal(t, "op [read] kind [unclassified error] trace_id [t1]", err.Error())
+	traceID, spanID, ok = errors.TraceIDs(err)
+	require.True(t, ok)
+	require.Equal(t, "t1", traceID)
+	require.Equal(t, "", spanID)
+
+	for _, err := range []error{nil, io.EOF, errors.NoTrace("read")} {
+		traceID, spanID, ok = errors.TraceIDs(err)
+		require.False(t, ok)
+		require.Empty(t, traceID)
+		require.Empty(t, spanID)
+	}
+}
+
+func TestFromSpanContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), traceKey{}, traceIDs{"t1", "s1"})
+
+	// no extractor
+	err := errors.FromSpanContext(ctx, "fetch", errors.K.Unavailable)
+	_, _, ok := errors.TraceIDs(err)
+	require.False(t, ok)
+
+	withTraceExtractor(t)
+
+	err = errors.FromSpanContext(ctx, "fetch", errors.K.Unavailable, "url", "http://a.b")
+	require.Equal(t, "op [fetch] kind [service unavailable] url [http://a.b] trace_id [t1] span_id [s1]", err.ErrorNoTrace())
+
+	err = errors.FromSpanContext(context.Background(), "fetch")
+	require.Equal(t, "op [fetch] kind [unclassified error]", err.ErrorNoTrace())
+
+	//nolint:staticcheck
+	err = errors.FromSpanContext(nil, "fetch")
+	require.Equal(t, "op [fetch] kind [unclassified error]", err.ErrorNoTrace())
+}
+
+func TestFromSpanContext_OnCreate(t *testing.T) {
+	withTraceExtractor(t)
+	defer func() { errors.OnCreate = nil }()
+
+	var traceID string
+	errors.OnCreate = func(e *errors.Error) {
+		traceID, _, _ = errors.TraceIDs(e)
+	}
+
+	ctx := context.WithValue(context.Background(), traceKey{}, traceIDs{"t1", "s1"})
+	_ = errors.FromSpanContext(ctx, "fetch")
+	require.Equal(t, "t1", traceID)
+}
+
+func TestFromContext_Trace(t *testing.T) {
+	withTraceExtractor(t)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, traceIDs{"t1", "s1"}))
+	require.Nil(t, errors.FromContext(ctx, "fetch"))
+
+	cancel()
+	err := errors.FromContext(ctx, "fetch")
+	require.Equal(t, "op [fetch] kind [operation cancelled] trace_id [t1] span_id [s1]", err.ErrorNoTrace())
+
+	// no trace IDs in context
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = errors.FromContext(ctx, "fetch")
+	require.Equal(t, "op [fetch] kind [operation cancelled]", err.ErrorNoTrace())
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// TypedFields enables type-preserving JSON marshaling of field values. By default, field values are marshaled as plain
+// JSON values, and after a JSON round-trip numbers become float64 - e.g. a field set with With("count", 2) returns
+// float64(2) from Field("count") after unmarshaling.
+//
+// If enabled, field values of basic numeric or bool types are wrapped in a typed envelope when marshaling:
+//
+//	errors.E("op", "count", 2) --> {"op":"op","kind":"unclassified error","count":{"__type":"int","v":2}}
+//
+// and restored to their original type when unmarshaling. Strings and all other types are marshaled as usual. Both the
+// marshaling and the unmarshaling side have to enable TypedFields.
+var TypedFields = false
+
+const (
+	typedFieldTypeKey  = "__type"
+	typedFieldValueKey = "v"
+)
+
+// typedFieldTypes are the types supported by typed envelopes, keyed by their type name.
+var typedFieldTypes = func() map[string]reflect.Type {
+	res := map[string]reflect.Type{}
+	for _, v := range []interface{}{
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), false,
+	} {
+		t := reflect.TypeOf(v)
+		res[t.Name()] = t
+	}
+	return res
+}()
+
+// typedField is the JSON envelope used for field values if TypedFields is enabled.
+type typedField struct {
+	Type  string      `json:"__type"`
+	Value interface{} `json:"v"`
+}
+
+// toTypedField wraps the given value in a typed envelope if its type is supported. Returns the value unchanged
+// otherwise.
+func toTypedField(val interface{}) interface{} {
+	t := reflect.TypeOf(val)
+	if t == nil || typedFieldTypes[t.Name()] != t {
+		return val
+	}
+	return &typedField{Type: t.Name(), Value: val}
+}
+
+// fromTypedField decodes the given JSON text as typed envelope. Returns the restored value and true if successful,
+// nil and false if the JSON text is not a typed envelope or the type is not supported.
+func fromTypedField(b []byte) (interface{}, bool) {
+	var env map[string]json.RawMessage
+	if json.Unmarshal(b, &env) != nil || len(env) != 2 {
+		return nil, false
+	}
+	var typ string
+	if json.Unmarshal(env[typedFieldTypeKey], &typ) != nil {
+		return nil, false
+	}
+	t, ok := typedFieldTypes[typ]
+	if !ok {
+		return nil, false
+	}
+	v, ok := env[typedFieldValueKey]
+	if !ok {
+		return nil, false
+	}
+	ptr := reflect.New(t)
+	if json.Unmarshal(v, ptr.Interface()) != nil {
+		return nil, false
+	}
+	return ptr.Elem().Interface(), true
+}
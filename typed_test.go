@@ -0,0 +1,72 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestTypedFields(t *testing.T) {
+	defer func(typed bool) {
+		errors.TypedFields = typed
+	}(errors.TypedFields)
+
+	newError := func() *errors.Error {
+		return errors.NoTrace("op", errors.K.IO,
+			errors.NoTrace("inner", "attempt", uint8(3)),
+			"count", 2,
+			"size", int64(1<<62+1),
+			"ratio", float32(0.5),
+			"ok", true,
+			"name", "joe",
+			"eof", io.EOF)
+	}
+
+	roundTrip := func(t *testing.T, err *errors.Error) (string, *errors.Error) {
+		jsn, jerr := json.Marshal(err)
+		require.NoError(t, jerr)
+		var res errors.Error
+		require.NoError(t, json.Unmarshal(jsn, &res))
+		return string(jsn), &res
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		errors.TypedFields = false
+		jsn, res := roundTrip(t, newError())
+		require.Equal(t, `{"op":"op","kind":"I/O error","count":2,"size":4611686018427387905,"ratio":0.5,"ok":true,"name":"joe","eof":{},"cause":{"op":"inner","kind":"unclassified error","attempt":3}}`, jsn)
+		require.Equal(t, float64(2), res.Field("count"))
+		require.Equal(t, float64(3), errors.Field(res.Cause(), "attempt"))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		errors.TypedFields = true
+		jsn, res := roundTrip(t, newError())
+		require.Equal(t, `{"op":"op","kind":"I/O error",`+
+			`"count":{"__type":"int","v":2},`+
+			`"size":{"__type":"int64","v":4611686018427387905},`+
+			`"ratio":{"__type":"float32","v":0.5},`+
+			`"ok":{"__type":"bool","v":true},`+
+			`"name":"joe","eof":{},`+
+			`"cause":{"op":"inner","kind":"unclassified error","attempt":{"__type":"uint8","v":3}}}`, jsn)
+		require.Equal(t, "op", res.Op())
+		require.Equal(t, errors.K.IO, res.Kind())
+		require.Equal(t, 2, res.Field("count"))
+		require.Equal(t, int64(1<<62+1), res.Field("size"))
+		require.Equal(t, float32(0.5), res.Field("ratio"))
+		require.Equal(t, true, res.Field("ok"))
+		require.Equal(t, "joe", res.Field("name"))
+		require.Equal(t, uint8(3), errors.Field(res.Cause(), "attempt"))
+	})
+
+	t.Run("not an envelope", func(t *testing.T) {
+		errors.TypedFields = true
+		var res errors.Error
+		require.NoError(t, json.Unmarshal([]byte(`{"op":"op","f1":{"__type":"unknown","v":1},"f2":{"__type":"int","v":"x"}}`), &res))
+		require.IsType(t, &errors.Error{}, res.Field("f1"))
+		require.IsType(t, &errors.Error{}, res.Field("f2"))
+	})
+}
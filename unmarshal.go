@@ -43,6 +43,12 @@ type valOrMap struct {
 }
 
 func (s *valOrMap) UnmarshalJSON(b []byte) error {
+	if TypedFields {
+		if val, ok := fromTypedField(b); ok {
+			s.val = val
+			return nil
+		}
+	}
 	err := json.Unmarshal(b, &s.m)
 	if err == nil {
 		return nil
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/json"
+	"math"
+	"reflect"
 )
 
 // pad appends str to the buffer if the buffer already has some data.
@@ -32,3 +34,22 @@ func convertForJSONMarshalling(obj interface{}) (interface{}, bool) {
 	}
 	return obj, false
 }
+
+// toInt converts the given value of any integer or float type to an int, e.g. a number that was unmarshaled from JSON
+// as float64. Returns false if the value is not a number or not integral.
+func toInt(val interface{}) (int, bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != math.Trunc(f) {
+			return 0, false
+		}
+		return int(f), true
+	}
+	return 0, false
+}
@@ -0,0 +1,40 @@
+package errors
+
+// ValidationBuilder collects field-level validation errors. Create it with Validation().
+type ValidationBuilder struct {
+	list ErrorList
+}
+
+// Validation creates a builder for collecting field-level validation errors:
+//
+//	v := errors.Validation()
+//	if user.Name == "" {
+//		v.Field("user.name", "missing")
+//	}
+//	if user.Age < 0 {
+//		v.Field("user.age", "negative")
+//	}
+//	return v.ErrorOrNil()
+//
+// Each validation error is an *Error of kind K.Invalid with a "field" and a "reason" field. The errors are created
+// without stacktrace.
+func Validation() *ValidationBuilder {
+	return &ValidationBuilder{}
+}
+
+// Field adds a validation error for the field with the given path and returns the builder for call chaining.
+func (v *ValidationBuilder) Field(path string, reason string) *ValidationBuilder {
+	v.list.Append(NoTrace(K.Invalid, "field", path, "reason", reason))
+	return v
+}
+
+// Len returns the number of collected validation errors.
+func (v *ValidationBuilder) Len() int {
+	return len(v.list.Errors)
+}
+
+// ErrorOrNil returns nil if no validation errors were collected, the single validation error if there is exactly one,
+// or an *ErrorList with all validation errors otherwise. See ErrorList.ErrorOrNil().
+func (v *ValidationBuilder) ErrorOrNil() error {
+	return v.list.ErrorOrNil()
+}
@@ -0,0 +1,39 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/eluv-io/errors-go"
+)
+
+func TestValidation(t *testing.T) {
+	v := errors.Validation()
+	require.Equal(t, 0, v.Len())
+	require.Nil(t, v.ErrorOrNil())
+
+	v.Field("user.name", "missing")
+	require.Equal(t, 1, v.Len())
+	err := v.ErrorOrNil()
+	require.Equal(t, "kind [invalid] field [user.name] reason [missing]", err.Error())
+	require.True(t, errors.IsKind(errors.K.Invalid, err))
+
+	v.Field("user.age", "negative").Field("user.emails[1]", "invalid format")
+	require.Equal(t, 3, v.Len())
+	err = v.ErrorOrNil()
+	list, ok := err.(*errors.ErrorList)
+	require.True(t, ok)
+	require.Len(t, list.Errors, 3)
+	require.Equal(t, "user.emails[1]", errors.Field(list.Errors[2], "field"))
+	require.Equal(t, "invalid format", errors.Field(list.Errors[2], "reason"))
+
+	jsn, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, `{"errors":[`+
+		`{"kind":"invalid","field":"user.name","reason":"missing"},`+
+		`{"kind":"invalid","field":"user.age","reason":"negative"},`+
+		`{"kind":"invalid","field":"user.emails[1]","reason":"invalid format"}]}`,
+		string(jsn))
+}